@@ -0,0 +1,158 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"golang.org/x/oauth2"
+)
+
+// accountInfo is who a profile is logged in as, saved next to the token so
+// commands can show it without asking the API
+type accountInfo struct {
+	Email     string    `json:"email"`
+	UserID    string    `json:"user_id"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func getAccountFilePath(profile string) (string, error) {
+	profileDir, err := getProfileDir(profile)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(profileDir, accountFileName), nil
+}
+
+// loadAccountInfo returns the saved account, or false if there's none
+func loadAccountInfo() (accountInfo, bool) {
+	return loadProfileAccountInfo(activeProfile())
+}
+
+func loadProfileAccountInfo(profile string) (accountInfo, bool) {
+	var account accountInfo
+
+	accountFilePath, err := getAccountFilePath(profile)
+	if err != nil {
+		return account, false
+	}
+	data, err := os.ReadFile(accountFilePath)
+	if err != nil {
+		return account, false
+	}
+	if err := json.Unmarshal(data, &account); err != nil {
+		return accountInfo{}, false
+	}
+	return account, true
+}
+
+// saveAccountInfo is best effort; without it we just ask the API again
+func saveAccountInfo(account accountInfo) error {
+	accountFilePath, err := getAccountFilePath(activeProfile())
+	if err != nil {
+		return err
+	}
+
+	account.UpdatedAt = time.Now()
+	data, err := json.MarshalIndent(account, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(accountFilePath), 0700); err != nil {
+		return err
+	}
+	return os.WriteFile(accountFilePath, data, 0600)
+}
+
+func deleteAccountInfo() error {
+	accountFilePath, err := getAccountFilePath(activeProfile())
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(accountFilePath); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// fetchAccountInfo asks the API who token belongs to and saves the answer
+func fetchAccountInfo(token *oauth2.Token) (accountInfo, error) {
+	client := authClient(token)
+
+	url := "https://api.basic.tech/auth/userInfo"
+	resp, err := client.Get(url)
+	if err != nil {
+		return accountInfo{}, apiRequestError("error fetching user info", url, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return accountInfo{}, fmt.Errorf("received non-200 response fetching user info: %d", resp.StatusCode)
+	}
+
+	var user struct {
+		ID    string `json:"id"`
+		Email string `json:"email"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&user); err != nil {
+		return accountInfo{}, fmt.Errorf("error parsing JSON response: %v", err)
+	}
+
+	account := accountInfo{Email: user.Email, UserID: user.ID}
+	saveAccountInfo(account)
+	return account, nil
+}
+
+// getUserID returns the logged in user's ID, which projects list as their
+// owner, preferring the saved account
+func getUserID(token *oauth2.Token) (string, error) {
+	if account, ok := loadAccountInfo(); ok && account.UserID != "" {
+		return account.UserID, nil
+	}
+
+	account, err := fetchAccountInfo(token)
+	if err != nil {
+		return "", err
+	}
+	if account.UserID == "" {
+		return "", fmt.Errorf("user info has no id")
+	}
+	return account.UserID, nil
+}
+
+// accountJSON is what 'basic account --json' prints
+type accountJSON struct {
+	Email       string    `json:"email"`
+	UserID      string    `json:"user_id"`
+	Profile     string    `json:"profile"`
+	TokenExpiry time.Time `json:"token_expiry"`
+	// true when the API couldn't be reached and the saved account is shown
+	Cached bool `json:"cached,omitempty"`
+}
+
+func printAccountJSON(token *oauth2.Token) tea.Msg {
+	account, err := fetchAccountInfo(token)
+	cached := false
+	if err != nil {
+		saved, ok := loadAccountInfo()
+		if !ok {
+			return errorScreenMsg{errorMessage: err.Error()}
+		}
+		account, cached = saved, true
+	}
+
+	out, _ := json.MarshalIndent(accountJSON{
+		Email:       account.Email,
+		UserID:      account.UserID,
+		Profile:     activeProfile(),
+		TokenExpiry: token.Expiry,
+		Cached:      cached,
+	}, "", "  ")
+	fmt.Println(string(out))
+	return tea.Quit()
+}
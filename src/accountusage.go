@@ -0,0 +1,115 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"text/tabwriter"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"golang.org/x/oauth2"
+)
+
+// usageLimit is one plan limit and how much of it is used, e.g.
+// {name: "projects", used: 3, limit: 5}. A limit of 0 means unlimited.
+type usageLimit struct {
+	Name  string  `json:"name"`
+	Used  float64 `json:"used"`
+	Limit float64 `json:"limit"`
+	Unit  string  `json:"unit,omitempty"`
+}
+
+// errUsageUnavailable means the API has no usage information for this account
+var errUsageUnavailable = errors.New("usage information isn't available for your account or plan")
+
+func getAccountUsage(token *oauth2.Token) ([]usageLimit, error) {
+	url := "https://api.basic.tech/account/usage"
+	resp, err := authClient(token).Get(url)
+	if err != nil {
+		return nil, apiRequestError("error fetching account usage", url, err)
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound, http.StatusForbidden, http.StatusNotImplemented:
+		return nil, errUsageUnavailable
+	default:
+		return nil, fmt.Errorf("received non-200 response fetching account usage: %d", resp.StatusCode)
+	}
+
+	var response struct {
+		Data []usageLimit `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return nil, fmt.Errorf("error parsing JSON response: %v", err)
+	}
+	return response.Data, nil
+}
+
+func performAccountUsage() tea.Msg {
+	token, err := loadToken()
+	if err != nil || token == nil {
+		return errorScreenMsg{errorMessage: loggedOutError(err)}
+	}
+
+	limits, err := getAccountUsage(token)
+	if err != nil {
+		if hasFlag("--json") {
+			printJSONError(err.Error(), "")
+		} else {
+			fmt.Printf("Error: %v\n", err)
+		}
+		return tea.Quit()
+	}
+
+	if hasFlag("--json") {
+		out, _ := json.MarshalIndent(limits, "", "  ")
+		fmt.Println(string(out))
+		return tea.Quit()
+	}
+
+	if len(limits) == 0 {
+		fmt.Println("No usage limits found for your account")
+		return tea.Quit()
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "LIMIT\tUSED\tOF\t")
+	for _, l := range limits {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", l.Name, formatUsageAmount(l.Used, l.Unit), formatUsageLimit(l), usageWarning(l))
+	}
+	w.Flush()
+	return tea.Quit()
+}
+
+func formatUsageAmount(amount float64, unit string) string {
+	if unit == "" {
+		return fmt.Sprintf("%.0f", amount)
+	}
+	return fmt.Sprintf("%.0f %s", amount, unit)
+}
+
+func formatUsageLimit(l usageLimit) string {
+	if l.Limit <= 0 {
+		return "unlimited"
+	}
+	return formatUsageAmount(l.Limit, l.Unit)
+}
+
+// usageWarning flags limits that are 80% or more used
+func usageWarning(l usageLimit) string {
+	if l.Limit <= 0 {
+		return ""
+	}
+	percent := l.Used / l.Limit * 100
+	if percent >= 100 {
+		return "limit reached"
+	}
+	if percent >= 80 {
+		return fmt.Sprintf("%.0f%% used", percent)
+	}
+	return ""
+}
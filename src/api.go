@@ -0,0 +1,56 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"golang.org/x/oauth2"
+)
+
+const defaultAPITimeout = 30 * time.Second
+
+// apiTimeout is how long a single API request may take, set with --api-timeout <seconds>
+func apiTimeout() time.Duration {
+	if value := flagValue("--api-timeout"); value != "" {
+		if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+			return time.Duration(seconds) * time.Second
+		}
+	}
+	return defaultAPITimeout
+}
+
+// apiClient is the shared client for unauthenticated API requests
+func apiClient() *http.Client {
+	return &http.Client{Timeout: apiTimeout()}
+}
+
+// apiContext makes oauth2 token requests use the shared client
+func apiContext() context.Context {
+	return context.WithValue(context.Background(), oauth2.HTTPClient, apiClient())
+}
+
+// authClient is the shared client for requests made with the user's token
+func authClient(token *oauth2.Token) *http.Client {
+	client := oauthConfig.Client(apiContext(), token)
+	client.Timeout = apiTimeout()
+	return client
+}
+
+// apiRequestError wraps a failed request, calling out timeouts so they aren't
+// mistaken for API errors
+func apiRequestError(action string, url string, err error) error {
+	if isTimeout(err) {
+		return fmt.Errorf("request to %s timed out after %s", url, apiTimeout())
+	}
+	return fmt.Errorf("%s: %v", action, err)
+}
+
+func isTimeout(err error) bool {
+	var netErr net.Error
+	return errors.Is(err, context.DeadlineExceeded) || (errors.As(err, &netErr) && netErr.Timeout())
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// defaultBackupKeep is how many backups per project are kept when --keep isn't set
+const defaultBackupKeep = 10
+
+// getBackupDir returns --backup-dir, or ~/.basic-cli/backups
+func getBackupDir() (string, error) {
+	if dir := flagValue("--backup-dir"); dir != "" {
+		return dir, nil
+	}
+	basicCliDir, err := getBasicCliDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(basicCliDir, backupsDirName), nil
+}
+
+// backupConfigFile copies the config to the backup dir before it's
+// overwritten, pruning old backups of the same project down to --keep.
+// It returns the path of the backup.
+func backupConfigFile(configFile, projectID string) (string, error) {
+	keep := defaultBackupKeep
+	if value := flagValue("--keep"); value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 1 {
+			return "", fmt.Errorf("--keep must be a number of 1 or more")
+		}
+		keep = n
+	}
+
+	content, err := os.ReadFile(configFile)
+	if err != nil {
+		return "", err
+	}
+
+	dir, err := getBackupDir()
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return "", err
+	}
+
+	// e.g. <project_id>-20240102-150405-basic.config.ts, sortable by time
+	prefix := projectID + "-"
+	name := prefix + time.Now().Format("20060102-150405") + "-" + filepath.Base(configFile)
+	backupPath := filepath.Join(dir, name)
+	if err := os.WriteFile(backupPath, content, 0600); err != nil {
+		return "", err
+	}
+
+	return backupPath, pruneBackups(dir, prefix, keep)
+}
+
+// pruneBackups removes all but the newest keep backups starting with prefix
+func pruneBackups(dir, prefix string, keep int) error {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+
+	var backups []string
+	for _, entry := range entries {
+		if !entry.IsDir() && strings.HasPrefix(entry.Name(), prefix) {
+			backups = append(backups, entry.Name())
+		}
+	}
+	sort.Strings(backups)
+
+	for len(backups) > keep {
+		if err := os.Remove(filepath.Join(dir, backups[0])); err != nil {
+			return err
+		}
+		backups = backups[1:]
+	}
+	return nil
+}
@@ -0,0 +1,293 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// switchFlags are the flags that don't take a value. Together with valueFlags
+// they're every flag the CLI knows, so anything else is a typo.
+var switchFlags = map[string]bool{
+	"-":                true, // read from stdin
+	"-h":               true,
+	"--help":           true,
+	"-y":               true,
+	"--yes":            true,
+	"--against-remote": true,
+	"--all":            true,
+	"--check":          true,
+	"--ci":             true,
+	"--confirm-remote": true,
+	"--csv":            true,
+	"--dry-run":        true,
+	"--explain":        true,
+	"--force-color":    true,
+	"--header":         true,
+	"--json":           true,
+	"--latest":         true,
+	"--no-fetch":       true,
+	"--offline":        true,
+	"--prompt":         true,
+	"--push":           true,
+	"--quiet":          true,
+	"--reverse":        true,
+	"--stdin":          true,
+	"--summary":        true,
+	"--token-expiry":   true,
+	"--watch":          true,
+	"--watch-version":  true,
+}
+
+// globalFlags work with every command
+var globalFlags = map[string]bool{
+	"-h":            true,
+	"--help":        true,
+	"-y":            true,
+	"--yes":         true,
+	"--api-timeout": true,
+	"--ci":          true,
+	"--force-color": true,
+	"--json":        true,
+	"--profile":     true,
+}
+
+var validateFlags = []string{"-", "--stdin", "--file", "--all", "--watch", "--against-remote", "--offline", "--quiet", "--summary", "--schema-index"}
+
+// commandFlags are the flags each command accepts on top of globalFlags, by
+// command or by command and subcommand like "schema rollback". Passing a flag
+// a command doesn't own is an error rather than silently doing nothing.
+var commandFlags = map[string][]string{
+	"account":  {"--token-expiry"},
+	"profile":  {},
+	"login":    {},
+	"logout":   {},
+	"reauth":   {},
+	"token":    {},
+	"auth":     {"--header"},
+	"status":   {"--explain", "--prompt", "--no-fetch", "--summary", "--ignore", "--schema-index", "--project", "--env"},
+	"push":     {"--confirm-remote", "--watch-version", "--dry-run", "--ignore", "--schema-index", "--project", "--env"},
+	"pull":     {"--check", "--ignore", "--schema-index", "--backup-dir", "--keep", "--config-out", "--project", "--env"},
+	"projects": {"--limit", "--csv", "--fields", "--sort", "--reverse", "--latest"},
+	"init":     {"--config-out"},
+	"version":  {},
+	"help":     {"--all"},
+
+	"schema":             {},
+	"schema watch":       {"--push"},
+	"schema validate":    validateFlags,
+	"schema unused":      {},
+	"schema import":      {},
+	"schema describe":    {},
+	"schema copy":        {},
+	"schema export":      {"--format", "--out"},
+	"schema init-remote": {},
+	"schema history":     {"--since", "--limit"},
+	"schema rollback":    {"--push", "--backup-dir", "--keep"},
+
+	"generate": {"--out", "--file", "--watch"},
+	"watch":    {"--offline"},
+	"env":      {},
+	"migrate":  {"--ignore", "--schema-index", "--project", "--env"},
+	"keys":     {},
+	"team":     {"--role"},
+	"history":  {"--since", "--limit"},
+	"diff":     {"--ignore", "--schema-index", "--project", "--env"},
+	"data":     {"-", "--stdin", "--file", "--limit"},
+	"validate": validateFlags,
+	"update":   {"--channel"},
+	"debug":    {},
+	"doctor":   {},
+	"feedback": {},
+	"hi":       {},
+	"logo":     {},
+}
+
+// commandFlagsFor returns the flags of command, or of its subcommand when it
+// has its own, with the name they're listed under
+func commandFlagsFor(command string) (string, []string, bool) {
+	if args := positionalArgs(); len(args) > 0 {
+		if flags, ok := commandFlags[command+" "+args[0]]; ok {
+			return command + " " + args[0], flags, true
+		}
+	}
+	flags, ok := commandFlags[command]
+	return command, flags, ok
+}
+
+// commandAccepts reports whether command takes flag. Unknown commands take
+// every flag, as they fail on their own.
+func commandAccepts(command, flag string) bool {
+	if globalFlags[flag] {
+		return true
+	}
+	_, flags, ok := commandFlagsFor(command)
+	if !ok {
+		return true
+	}
+	for _, f := range flags {
+		if f == flag {
+			return true
+		}
+	}
+	return false
+}
+
+// checkFlags returns an error for the first flag the CLI doesn't know, for a
+// flag the command doesn't take, or for a value flag that's missing its value
+func checkFlags(command string) error {
+	for i := 0; i < len(cliArgs); i++ {
+		arg := cliArgs[i]
+		if !strings.HasPrefix(arg, "-") {
+			continue
+		}
+
+		name, value, hasValue := strings.Cut(arg, "=")
+		if valueFlags[name] || switchFlags[name] {
+			if !commandAccepts(command, name) {
+				owner, _, _ := commandFlagsFor(command)
+				return fmt.Errorf("'basic %s' doesn't take %s\nrun 'basic %s --help' to see what it accepts", owner, name, owner)
+			}
+		}
+		if valueFlags[name] {
+			if !hasValue {
+				if i+1 >= len(cliArgs) {
+					return fmt.Errorf("flag %s needs a value", name)
+				}
+				i++
+				value = cliArgs[i]
+			}
+			if err := checkFlagValue(name, value); err != nil {
+				return err
+			}
+			continue
+		}
+		if switchFlags[name] {
+			continue
+		}
+
+		err := fmt.Sprintf("unknown flag %s", name)
+		if suggestion := similarFlag(name); suggestion != "" {
+			err += fmt.Sprintf(", did you mean %s?", suggestion)
+		}
+		return fmt.Errorf("%s\nrun 'basic %s --help' to see what it accepts", err, command)
+	}
+	return nil
+}
+
+// checkFlagValue returns an error for a malformed value of a flag that every
+// command reads, so a typo doesn't quietly fall back to the default
+func checkFlagValue(name, value string) error {
+	switch name {
+	case "--api-timeout":
+		if seconds, err := strconv.Atoi(value); err != nil || seconds <= 0 {
+			return fmt.Errorf("--api-timeout must be a whole number of seconds above 0, got %q", value)
+		}
+	}
+	return nil
+}
+
+// similarFlag returns the known flag closest to name, or "" if none is close
+func similarFlag(name string) string {
+	var flags []string
+	for _, known := range []map[string]bool{switchFlags, valueFlags} {
+		for flag := range known {
+			flags = append(flags, flag)
+		}
+	}
+	sort.Strings(flags)
+
+	best, bestSimilarity := "", 0.0
+	for _, flag := range flags {
+		if sim := similarity(name, flag); sim >= similarityThreshold && sim > bestSimilarity {
+			best, bestSimilarity = flag, sim
+		}
+	}
+	return best
+}
+
+// printCommandHelp implements 'basic <command> --help' and 'basic help
+// <command>', listing the command and its subcommands. It returns the exit code.
+func printCommandHelp(command string) int {
+	if target, ok := commandAliases[command]; ok {
+		command = target
+	}
+
+	var entries []commandInfo
+	for _, c := range append(append([]commandInfo{}, commandList...), hiddenCommandList...) {
+		if c.name == command || strings.HasPrefix(c.name, command+" ") {
+			entries = append(entries, c)
+		}
+	}
+
+	if len(entries) == 0 && hasFlag("--json") {
+		printJSONError(unknownCommandError(command, findSimilarCommands(command)), "usage")
+		return exitUsage
+	}
+	if len(entries) == 0 {
+		fmt.Fprintf(os.Stderr, "Unknown command: %s\n", command)
+		if suggestions := findSimilarCommands(command); len(suggestions) > 0 {
+			fmt.Fprintf(os.Stderr, "Did you mean: %s?\n", strings.Join(suggestions, ", "))
+		}
+		fmt.Fprintln(os.Stderr, "Run 'basic help' to see all commands")
+		return exitUsage
+	}
+
+	if hasFlag("--json") {
+		printHelpJSON(entries)
+		return exitOK
+	}
+
+	fmt.Println("Usage:")
+	for _, c := range entries {
+		fmt.Printf("  basic %s - %s\n", c.name, c.description)
+	}
+
+	var aliases []string
+	for alias, target := range commandAliases {
+		if target == command {
+			aliases = append(aliases, alias)
+		}
+	}
+	if len(aliases) > 0 {
+		sort.Strings(aliases)
+		fmt.Printf("\nAliases: %s\n", strings.Join(aliases, ", "))
+	}
+
+	fmt.Println("\nGlobal flags:")
+	for _, f := range globalFlagList {
+		fmt.Printf("  %s - %s\n", f.name, f.description)
+	}
+	return exitOK
+}
+
+// helpJSON is what 'basic help --json' prints
+type helpJSON struct {
+	Commands    []helpEntryJSON   `json:"commands"`
+	Aliases     map[string]string `json:"aliases"`
+	GlobalFlags []helpEntryJSON   `json:"global_flags"`
+}
+
+type helpEntryJSON struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+func printHelpJSON(entries []commandInfo) {
+	toJSON := func(infos []commandInfo) []helpEntryJSON {
+		out := make([]helpEntryJSON, len(infos))
+		for i, info := range infos {
+			out[i] = helpEntryJSON{Name: info.name, Description: info.description}
+		}
+		return out
+	}
+	out, _ := json.MarshalIndent(helpJSON{
+		Commands:    toJSON(entries),
+		Aliases:     commandAliases,
+		GlobalFlags: toJSON(globalFlagList),
+	}, "", "  ")
+	fmt.Println(string(out))
+}
@@ -0,0 +1,1002 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// The config is a JS/TS module, so reading the schema out of it takes a
+// tokenizer that knows about strings, template literals, comments and regular
+// expressions, and a parser for the object literal the schema is assigned.
+// Writing the schema back only replaces that literal, and within it keeps
+// comments and the text of every value that didn't change.
+
+type jsTokenKind int
+
+const (
+	jsIdentifier jsTokenKind = iota
+	jsString
+	jsNumber
+	jsPunct
+	jsComment
+	// regular expressions, which only need to be skipped
+	jsOther
+)
+
+type jsToken struct {
+	kind jsTokenKind
+	text string
+	// byte offsets in the source
+	start, end int
+	// template literal with ${...} in it
+	hasExpr bool
+}
+
+// jsNode is a literal value in the source
+type jsNode struct {
+	kind  string // "object", "array", "string", "number", "bool" or "null"
+	value interface{}
+	props []*jsProperty
+	items []*jsNode
+	// byte offsets of the value in the source, and its first and last token
+	start, end  int
+	first, last int
+
+	trailingComma bool
+	// comments after the last entry of an object
+	closingComments []string
+}
+
+type jsProperty struct {
+	key      string
+	rawKey   string
+	keyStart int
+	value    *jsNode
+	// comments on their own lines before the key
+	comments []string
+	// a comment on the same line, after the entry
+	trailing string
+}
+
+// get returns the value of key, if node is an object that has it
+func (node *jsNode) get(key string) *jsNode {
+	for _, prop := range node.props {
+		if prop.key == key {
+			return prop.value
+		}
+	}
+	return nil
+}
+
+// toValue returns node as encoding/json would decode it
+func (node *jsNode) toValue() interface{} {
+	switch node.kind {
+	case "object":
+		m := make(map[string]interface{}, len(node.props))
+		for _, prop := range node.props {
+			m[prop.key] = prop.value.toValue()
+		}
+		return m
+	case "array":
+		items := make([]interface{}, len(node.items))
+		for i, item := range node.items {
+			items[i] = item.toValue()
+		}
+		return items
+	}
+	return node.value
+}
+
+type jsParser struct {
+	src    string
+	tokens []jsToken
+	pos    int
+
+	// tokens the JSON conversion rewrites, by index
+	keys           map[int]bool
+	trailingCommas map[int]bool
+	signs          map[int]bool
+}
+
+func newJSParser(src string) (*jsParser, error) {
+	tokens, err := tokenizeJS(src)
+	if err != nil {
+		return nil, err
+	}
+	return &jsParser{src: src, tokens: tokens, keys: map[int]bool{}, trailingCommas: map[int]bool{}, signs: map[int]bool{}}, nil
+}
+
+// lineOf returns the 1-based line of offset in src
+func lineOf(src string, offset int) int {
+	return 1 + strings.Count(src[:offset], "\n")
+}
+
+func (p *jsParser) errorf(offset int, format string, args ...interface{}) error {
+	return fmt.Errorf("line %d: %s", lineOf(p.src, offset), fmt.Sprintf(format, args...))
+}
+
+// tokenizeJS splits src into tokens. It only fails on strings, template
+// literals and comments that are never closed.
+func tokenizeJS(src string) ([]jsToken, error) {
+	var tokens []jsToken
+	var prev *jsToken
+	for i := 0; ; {
+		tok, ok, err := nextJSToken(src, i, prev)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			return tokens, nil
+		}
+		tokens = append(tokens, tok)
+		if tok.kind != jsComment {
+			prev = &tokens[len(tokens)-1]
+		}
+		i = tok.end
+	}
+}
+
+// nextJSToken reads the token at or after offset i. prev is the last token
+// that isn't a comment, to tell a regular expression from a division.
+func nextJSToken(src string, i int, prev *jsToken) (jsToken, bool, error) {
+	for i < len(src) {
+		r, size := utf8.DecodeRuneInString(src[i:])
+		if !unicode.IsSpace(r) && r != '\uFEFF' {
+			break
+		}
+		i += size
+	}
+	if i >= len(src) {
+		return jsToken{}, false, nil
+	}
+
+	start := i
+	token := func(kind jsTokenKind, end int) (jsToken, bool, error) {
+		return jsToken{kind: kind, text: src[start:end], start: start, end: end}, true, nil
+	}
+	c := src[i]
+	switch {
+	case strings.HasPrefix(src[i:], "//"):
+		end := strings.IndexByte(src[i:], '\n')
+		if end < 0 {
+			return token(jsComment, len(src))
+		}
+		return token(jsComment, i+end)
+	case strings.HasPrefix(src[i:], "/*"):
+		end := strings.Index(src[i+2:], "*/")
+		if end < 0 {
+			return jsToken{}, false, fmt.Errorf("line %d: comment is never closed", lineOf(src, i))
+		}
+		return token(jsComment, i+2+end+2)
+	case c == '"' || c == '\'':
+		for j := i + 1; j < len(src); j++ {
+			switch src[j] {
+			case '\\':
+				j++
+			case c:
+				return token(jsString, j+1)
+			case '\n':
+				j = len(src)
+			}
+		}
+		return jsToken{}, false, fmt.Errorf("line %d: string is never closed", lineOf(src, i))
+	case c == '`':
+		end, hasExpr, err := scanTemplate(src, i)
+		if err != nil {
+			return jsToken{}, false, err
+		}
+		tok, ok, _ := token(jsString, end)
+		tok.hasExpr = hasExpr
+		return tok, ok, nil
+	case c >= '0' && c <= '9' || c == '.' && i+1 < len(src) && src[i+1] >= '0' && src[i+1] <= '9':
+		j := i + 1
+		hex := strings.HasPrefix(strings.ToLower(src[i:]), "0x")
+		for j < len(src) {
+			d := src[j]
+			if d == '_' || d == '.' || d >= '0' && d <= '9' || d >= 'a' && d <= 'z' || d >= 'A' && d <= 'Z' {
+				j++
+			} else if (d == '+' || d == '-') && !hex && (src[j-1] == 'e' || src[j-1] == 'E') {
+				j++
+			} else {
+				break
+			}
+		}
+		return token(jsNumber, j)
+	case c == '/' && !endsExpression(prev):
+		return scanRegexp(src, i)
+	}
+
+	r, size := utf8.DecodeRuneInString(src[i:])
+	if r == '_' || r == '$' || unicode.IsLetter(r) {
+		j := i + size
+		for j < len(src) {
+			r, size := utf8.DecodeRuneInString(src[j:])
+			if r != '_' && r != '$' && !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+				break
+			}
+			j += size
+		}
+		return token(jsIdentifier, j)
+	}
+	return token(jsPunct, i+size)
+}
+
+// endsExpression reports whether a / after tok divides it, rather than
+// starting a regular expression
+func endsExpression(tok *jsToken) bool {
+	if tok == nil {
+		return false
+	}
+	switch tok.kind {
+	case jsIdentifier, jsNumber, jsString, jsOther:
+		return true
+	case jsPunct:
+		return tok.text == ")" || tok.text == "]" || tok.text == "}"
+	}
+	return false
+}
+
+func scanRegexp(src string, i int) (jsToken, bool, error) {
+	inClass := false
+	for j := i + 1; j < len(src); j++ {
+		switch src[j] {
+		case '\\':
+			j++
+		case '[':
+			inClass = true
+		case ']':
+			inClass = false
+		case '\n':
+			j = len(src)
+		case '/':
+			if inClass {
+				continue
+			}
+			end := j + 1
+			for end < len(src) && (src[end] >= 'a' && src[end] <= 'z') {
+				end++
+			}
+			return jsToken{kind: jsOther, text: src[i:end], start: i, end: end}, true, nil
+		}
+	}
+	// not a regular expression after all, so a lone slash
+	return jsToken{kind: jsPunct, text: "/", start: i, end: i + 1}, true, nil
+}
+
+// scanTemplate returns the end of the template literal at i, skipping the
+// code in its ${...} placeholders
+func scanTemplate(src string, i int) (int, bool, error) {
+	hasExpr := false
+scan:
+	for j := i + 1; j < len(src); {
+		switch {
+		case src[j] == '\\':
+			j += 2
+		case src[j] == '`':
+			return j + 1, hasExpr, nil
+		case strings.HasPrefix(src[j:], "${"):
+			hasExpr = true
+			depth := 0
+			var prev *jsToken
+			for k := j + 2; ; {
+				tok, ok, err := nextJSToken(src, k, prev)
+				if err != nil {
+					return 0, false, err
+				}
+				if !ok {
+					return 0, false, fmt.Errorf("line %d: template literal is never closed", lineOf(src, i))
+				}
+				k = tok.end
+				if tok.kind == jsComment {
+					continue
+				}
+				prev = &tok
+				if tok.text == "{" {
+					depth++
+				} else if tok.text == "}" {
+					if depth == 0 {
+						j = k
+						continue scan
+					}
+					depth--
+				}
+			}
+		default:
+			j++
+		}
+	}
+	return 0, false, fmt.Errorf("line %d: template literal is never closed", lineOf(src, i))
+}
+
+// peek returns the index of the next token that isn't a comment, or -1 at the
+// end of the source
+func (p *jsParser) peek() int {
+	for i := p.pos; i < len(p.tokens); i++ {
+		if p.tokens[i].kind != jsComment {
+			return i
+		}
+	}
+	return -1
+}
+
+// peekText returns the text of the next token that isn't a comment
+func (p *jsParser) peekText() string {
+	if i := p.peek(); i >= 0 {
+		return p.tokens[i].text
+	}
+	return ""
+}
+
+// comments returns the comments before the next token, and moves past them
+func (p *jsParser) comments() []jsToken {
+	var comments []jsToken
+	for p.pos < len(p.tokens) && p.tokens[p.pos].kind == jsComment {
+		comments = append(comments, p.tokens[p.pos])
+		p.pos++
+	}
+	return comments
+}
+
+func (p *jsParser) unexpected(i int, expected string) error {
+	if i < 0 {
+		return p.errorf(len(p.src), "expected %s, found the end of the file", expected)
+	}
+	return p.errorf(p.tokens[i].start, "expected %s, found %s", expected, p.tokens[i].text)
+}
+
+// findObject parses the object literal assigned to name, as in
+// `const name = {...}`, `name: Type = {...}` or `name: {...}`. It returns nil
+// if there's none.
+func (p *jsParser) findObject(name string) (*jsNode, error) {
+	prev := ""
+	for i, tok := range p.tokens {
+		if tok.kind == jsComment {
+			continue
+		}
+		if tok.kind != jsIdentifier || tok.text != name || prev == "." {
+			prev = tok.text
+			continue
+		}
+		prev = tok.text
+
+		p.pos = i + 1
+		switch p.peekText() {
+		case ":":
+			p.pos = p.peek() + 1
+			if p.peekText() == "{" {
+				return p.parseValue()
+			}
+			// a type annotation, then the assignment
+			for {
+				j := p.peek()
+				if j < 0 || p.tokens[j].kind != jsIdentifier && !strings.Contains(".<>[],", p.tokens[j].text) {
+					break
+				}
+				p.pos = j + 1
+			}
+			if p.peekText() != "=" {
+				continue
+			}
+			fallthrough
+		case "=":
+			p.pos = p.peek() + 1
+			if p.peekText() == "{" {
+				return p.parseValue()
+			}
+		}
+	}
+	return nil, nil
+}
+
+func (p *jsParser) parseValue() (*jsNode, error) {
+	i := p.peek()
+	if i < 0 {
+		return nil, p.unexpected(i, "a value")
+	}
+	tok := p.tokens[i]
+	p.pos = i + 1
+	node := &jsNode{start: tok.start, end: tok.end, first: i, last: i}
+
+	switch {
+	case tok.text == "{":
+		return p.parseObject(node)
+	case tok.text == "[":
+		return p.parseArray(node)
+	case tok.kind == jsString:
+		value, err := p.decodeString(tok)
+		if err != nil {
+			return nil, err
+		}
+		node.kind, node.value = "string", value
+	case tok.kind == jsNumber:
+		value, err := parseJSNumber(tok.text)
+		if err != nil {
+			return nil, p.errorf(tok.start, "invalid number %s", tok.text)
+		}
+		node.kind, node.value = "number", value
+	case tok.text == "-" || tok.text == "+":
+		j := p.peek()
+		if j < 0 || p.tokens[j].kind != jsNumber {
+			return nil, p.unexpected(j, "a number after "+tok.text)
+		}
+		p.signs[i] = true
+		number, err := p.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		if tok.text == "-" {
+			number.value = -number.value.(float64)
+		}
+		number.start, number.first = node.start, node.first
+		return number, nil
+	case tok.text == "true" || tok.text == "false":
+		node.kind, node.value = "bool", tok.text == "true"
+	case tok.text == "null":
+		node.kind = "null"
+	case tok.kind == jsIdentifier:
+		return nil, p.errorf(tok.start, "%s isn't a literal, the schema can only contain plain values", tok.text)
+	default:
+		return nil, p.unexpected(i, "a value")
+	}
+	return node, nil
+}
+
+func (p *jsParser) parseObject(node *jsNode) (*jsNode, error) {
+	node.kind = "object"
+	// end of the last entry, to tell its trailing comment from the next
+	// entry's comments
+	lastEnd := -1
+	for {
+		comments := p.comments()
+		var own []string
+		for _, c := range comments {
+			if len(node.props) > 0 && lastEnd >= 0 && !strings.Contains(p.src[lastEnd:c.start], "\n") {
+				node.props[len(node.props)-1].trailing = c.text
+				continue
+			}
+			own = append(own, c.text)
+		}
+
+		i := p.peek()
+		if i < 0 {
+			return nil, p.unexpected(i, "'}'")
+		}
+		tok := p.tokens[i]
+		if tok.text == "}" {
+			node.closingComments = own
+			p.pos = i + 1
+			node.end, node.last = tok.end, i
+			return node, nil
+		}
+
+		prop := &jsProperty{rawKey: tok.text, keyStart: tok.start, comments: own}
+		switch tok.kind {
+		case jsIdentifier:
+			prop.key = tok.text
+		case jsString:
+			key, err := p.decodeString(tok)
+			if err != nil {
+				return nil, err
+			}
+			prop.key = key
+		case jsNumber:
+			number, err := parseJSNumber(tok.text)
+			if err != nil {
+				return nil, p.errorf(tok.start, "invalid number %s", tok.text)
+			}
+			prop.key = strconv.FormatFloat(number, 'f', -1, 64)
+		default:
+			return nil, p.unexpected(i, "a key")
+		}
+		p.keys[i] = true
+		p.pos = i + 1
+
+		if j := p.peek(); j < 0 || p.tokens[j].text != ":" {
+			return nil, p.unexpected(j, fmt.Sprintf("':' after %s", tok.text))
+		}
+		p.pos = p.peek() + 1
+		value, err := p.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		prop.value = value
+		node.props = append(node.props, prop)
+		lastEnd = value.end
+
+		j := p.peek()
+		switch {
+		case j >= 0 && p.tokens[j].text == ",":
+			p.pos = j + 1
+			lastEnd = p.tokens[j].end
+			if p.peekText() == "}" {
+				p.trailingCommas[j] = true
+				node.trailingComma = true
+			}
+		case j >= 0 && p.tokens[j].text == "}":
+		default:
+			return nil, p.unexpected(j, "',' or '}'")
+		}
+	}
+}
+
+func (p *jsParser) parseArray(node *jsNode) (*jsNode, error) {
+	node.kind = "array"
+	for {
+		i := p.peek()
+		if i < 0 {
+			return nil, p.unexpected(i, "']'")
+		}
+		if p.tokens[i].text == "]" {
+			p.pos = i + 1
+			node.end, node.last = p.tokens[i].end, i
+			return node, nil
+		}
+
+		item, err := p.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		node.items = append(node.items, item)
+
+		j := p.peek()
+		switch {
+		case j >= 0 && p.tokens[j].text == ",":
+			p.pos = j + 1
+			if p.peekText() == "]" {
+				p.trailingCommas[j] = true
+				node.trailingComma = true
+			}
+		case j >= 0 && p.tokens[j].text == "]":
+		default:
+			return nil, p.unexpected(j, "',' or ']'")
+		}
+	}
+}
+
+func (p *jsParser) decodeString(tok jsToken) (string, error) {
+	if tok.hasExpr {
+		return "", p.errorf(tok.start, "template literals with ${...} aren't supported in the schema")
+	}
+	value, err := decodeJSString(tok.text)
+	if err != nil {
+		return "", p.errorf(tok.start, "%v", err)
+	}
+	return value, nil
+}
+
+// decodeJSString decodes a quoted JS string or template literal
+func decodeJSString(raw string) (string, error) {
+	body := raw[1 : len(raw)-1]
+	var s strings.Builder
+	for i := 0; i < len(body); i++ {
+		if body[i] != '\\' {
+			s.WriteByte(body[i])
+			continue
+		}
+		i++
+		if i >= len(body) {
+			return "", fmt.Errorf("string ends in a backslash")
+		}
+		switch c := body[i]; c {
+		case 'n':
+			s.WriteByte('\n')
+		case 't':
+			s.WriteByte('\t')
+		case 'r':
+			s.WriteByte('\r')
+		case 'b':
+			s.WriteByte('\b')
+		case 'f':
+			s.WriteByte('\f')
+		case 'v':
+			s.WriteByte('\v')
+		case '0':
+			s.WriteByte(0)
+		case '\r':
+			// line continuation
+			if i+1 < len(body) && body[i+1] == '\n' {
+				i++
+			}
+		case '\n':
+		case 'x', 'u':
+			digits := 2
+			if c == 'u' {
+				digits = 4
+				if i+1 < len(body) && body[i+1] == '{' {
+					end := strings.IndexByte(body[i:], '}')
+					if end < 0 {
+						return "", fmt.Errorf("invalid escape \\u%s", body[i+1:])
+					}
+					code, err := strconv.ParseUint(body[i+2:i+end], 16, 32)
+					if err != nil {
+						return "", fmt.Errorf("invalid escape \\u%s", body[i+1:i+end+1])
+					}
+					s.WriteRune(rune(code))
+					i += end
+					continue
+				}
+			}
+			if i+1+digits > len(body) {
+				return "", fmt.Errorf("invalid escape \\%s", body[i:])
+			}
+			code, err := strconv.ParseUint(body[i+1:i+1+digits], 16, 32)
+			if err != nil {
+				return "", fmt.Errorf("invalid escape \\%s", body[i:i+1+digits])
+			}
+			s.WriteRune(rune(code))
+			i += digits
+		default:
+			s.WriteByte(c)
+		}
+	}
+	return s.String(), nil
+}
+
+// parseJSNumber parses a JS number literal, like 1_000, .5 or 0xff
+func parseJSNumber(text string) (float64, error) {
+	clean := strings.ReplaceAll(text, "_", "")
+	if len(clean) > 1 && clean[0] == '0' && strings.ContainsAny(clean[1:2], "xXoObB") {
+		n, err := strconv.ParseInt(clean, 0, 64)
+		return float64(n), err
+	}
+	if len(clean) > 1 && clean[0] == '0' && clean[1] >= '0' && clean[1] <= '9' {
+		return 0, fmt.Errorf("legacy octal literal")
+	}
+	return strconv.ParseFloat(clean, 64)
+}
+
+// toJSON converts node to JSON, keeping its layout: line breaks and
+// indentation stay where they are and comments become spaces, so lines and
+// columns in the JSON are those of the source
+func (p *jsParser) toJSON(node *jsNode) string {
+	var s strings.Builder
+	prevEnd := p.tokens[node.first].start
+	skipGap := false
+	for i := node.first; i <= node.last; i++ {
+		tok := p.tokens[i]
+		if !skipGap {
+			s.WriteString(jsonWhitespace(p.src[prevEnd:tok.start]))
+		}
+		skipGap = false
+		prevEnd = tok.end
+
+		switch {
+		case tok.kind == jsComment:
+			s.WriteString(jsonWhitespace(tok.text))
+		case p.signs[i]:
+			if tok.text == "-" {
+				s.WriteString("-")
+			}
+			skipGap = true
+		case p.trailingCommas[i]:
+			s.WriteString(" ")
+		case tok.kind == jsString:
+			value, _ := decodeJSString(tok.text)
+			s.WriteString(jsonString(value))
+			// keep the following lines in place after a multi-line template
+			s.WriteString(strings.Repeat("\n", strings.Count(tok.text, "\n")))
+		case tok.kind == jsNumber && p.keys[i]:
+			number, _ := parseJSNumber(tok.text)
+			s.WriteString(jsonString(strconv.FormatFloat(number, 'f', -1, 64)))
+		case tok.kind == jsNumber:
+			s.WriteString(jsonNumber(tok.text))
+		case tok.kind == jsIdentifier && p.keys[i]:
+			s.WriteString(jsonString(tok.text))
+		default:
+			s.WriteString(tok.text)
+		}
+	}
+	return s.String()
+}
+
+// jsonWhitespace blanks out everything in s but line breaks and tabs
+func jsonWhitespace(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '\n' || r == '\t' || r == '\r' {
+			return r
+		}
+		return ' '
+	}, s)
+}
+
+func jsonString(s string) string {
+	var b bytes.Buffer
+	encoder := json.NewEncoder(&b)
+	encoder.SetEscapeHTML(false)
+	encoder.Encode(s)
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+func jsonNumber(text string) string {
+	if json.Valid([]byte(text)) {
+		return text
+	}
+	number, _ := parseJSNumber(text)
+	return strconv.FormatFloat(number, 'g', -1, 64)
+}
+
+// parseJSValue parses src as a single value, such as a schema in JSON
+func parseJSValue(src string) (*jsParser, *jsNode, error) {
+	p, err := newJSParser(src)
+	if err != nil {
+		return nil, nil, err
+	}
+	node, err := p.parseValue()
+	if err != nil {
+		return nil, nil, err
+	}
+	if i := p.peek(); i >= 0 {
+		return nil, nil, p.unexpected(i, "the end of the value")
+	}
+	return p, node, nil
+}
+
+// readConfigSchema returns the schema of a JS/TS config as JSON laid out like
+// the source, and the line it starts on
+func readConfigSchema(src string) (string, int, error) {
+	p, err := newJSParser(src)
+	if err != nil {
+		return "", 0, err
+	}
+	node, err := p.findObject("schema")
+	if err != nil {
+		return "", 0, err
+	}
+	if node == nil {
+		return "", 0, errNoSchemaFound
+	}
+	return p.toJSON(node), lineOf(src, node.start), nil
+}
+
+// readConfigProjectID returns the project_id of the config block of a JS/TS
+// config, or of its schema when the config block has none. It returns "" if
+// neither has one.
+func readConfigProjectID(src string) (string, error) {
+	p, err := newJSParser(src)
+	if err != nil {
+		return "", err
+	}
+	for _, name := range []string{"config", "schema"} {
+		node, err := p.findObject(name)
+		if err != nil {
+			return "", err
+		}
+		if node == nil {
+			continue
+		}
+		if id := node.get("project_id"); id != nil && id.kind == "string" && id.value != "" {
+			return id.value.(string), nil
+		}
+	}
+	return "", nil
+}
+
+// replaceConfigSchema returns src with its schema replaced by schema, which
+// is JSON, and whether src had a schema to replace. Comments in the schema,
+// and the text of values that didn't change, are kept.
+func replaceConfigSchema(src, schema string) (string, bool, error) {
+	newParser, updated, err := parseJSValue(schema)
+	if err != nil {
+		return "", false, fmt.Errorf("invalid schema JSON: %v", err)
+	}
+
+	p, err := newJSParser(src)
+	if err != nil {
+		return "", false, err
+	}
+	old, err := p.findObject("schema")
+	if err != nil {
+		return "", false, err
+	}
+
+	if old == nil {
+		return src, false, nil
+	}
+	r := &jsRenderer{src: src, newSrc: newParser.src, indent: detectIndent(src, old), quoteKeys: prefersQuotedKeys(old), trailingCommas: old.trailingComma}
+	rendered := r.render(updated, old, lineIndent(src, old.start))
+	return src[:old.start] + rendered + src[old.end:], true, nil
+}
+
+// renderNewSchema renders schema, which is JSON, to be appended to a config
+func renderNewSchema(schema string) (string, error) {
+	p, node, err := parseJSValue(schema)
+	if err != nil {
+		return "", fmt.Errorf("invalid schema JSON: %v", err)
+	}
+	r := &jsRenderer{newSrc: p.src, indent: "  ", quoteKeys: true}
+	return r.render(node, nil, ""), nil
+}
+
+// jsRenderer writes a new value in place of an old one
+type jsRenderer struct {
+	// sources of the old and new values
+	src, newSrc string
+	// one level of indentation
+	indent    string
+	quoteKeys bool
+	// whether new objects end in a comma, like the old schema
+	trailingCommas bool
+}
+
+// render returns node, laid out like old where there is one. prefix is the
+// indentation of the line node starts on.
+func (r *jsRenderer) render(node, old *jsNode, prefix string) string {
+	if old != nil && reflect.DeepEqual(node.toValue(), old.toValue()) {
+		return r.src[old.start:old.end]
+	}
+	if old != nil && old.kind != node.kind {
+		old = nil
+	}
+
+	switch node.kind {
+	case "object":
+		if len(node.props) == 0 && (old == nil || len(old.closingComments) == 0) {
+			return "{}"
+		}
+		inner, closing := prefix+r.indent, prefix
+		if old != nil && len(old.props) > 0 && startsLine(r.src, old.props[0].keyStart) {
+			inner = lineIndent(r.src, old.props[0].keyStart)
+			if startsLine(r.src, old.end-1) {
+				closing = lineIndent(r.src, old.end-1)
+			}
+		}
+
+		type entry struct{ prop, old *jsProperty }
+		var entries []entry
+		seen := map[string]bool{}
+		if old != nil {
+			// keep the order of the old object, with new keys at the end
+			for _, oldProp := range old.props {
+				for _, prop := range node.props {
+					if prop.key == oldProp.key && !seen[prop.key] {
+						entries = append(entries, entry{prop, oldProp})
+						seen[prop.key] = true
+					}
+				}
+			}
+		}
+		for _, prop := range node.props {
+			if !seen[prop.key] {
+				entries = append(entries, entry{prop, nil})
+				seen[prop.key] = true
+			}
+		}
+
+		var s strings.Builder
+		s.WriteString("{\n")
+		for i, e := range entries {
+			key := r.key(e.prop.key)
+			var oldValue *jsNode
+			if e.old != nil {
+				for _, comment := range e.old.comments {
+					s.WriteString(inner + comment + "\n")
+				}
+				key, oldValue = e.old.rawKey, e.old.value
+			}
+			s.WriteString(inner + key + ": " + r.render(e.prop.value, oldValue, inner))
+			trailingComma := r.trailingCommas
+			if old != nil {
+				trailingComma = old.trailingComma
+			}
+			if i < len(entries)-1 || trailingComma {
+				s.WriteString(",")
+			}
+			if e.old != nil && e.old.trailing != "" {
+				s.WriteString(" " + e.old.trailing)
+			}
+			s.WriteString("\n")
+		}
+		if old != nil {
+			for _, comment := range old.closingComments {
+				s.WriteString(inner + comment + "\n")
+			}
+		}
+		s.WriteString(closing + "}")
+		return s.String()
+	case "array":
+		items := make([]string, len(node.items))
+		multiline := false
+		for i, item := range node.items {
+			var oldItem *jsNode
+			if old != nil && i < len(old.items) {
+				oldItem = old.items[i]
+			}
+			items[i] = r.render(item, oldItem, prefix+r.indent)
+			multiline = multiline || item.kind == "object" || item.kind == "array"
+		}
+		if !multiline {
+			return "[" + strings.Join(items, ", ") + "]"
+		}
+		inner := prefix + r.indent
+		return "[\n" + inner + strings.Join(items, ",\n"+inner) + "\n" + prefix + "]"
+	case "string":
+		if old != nil && r.src[old.start] == '\'' {
+			return singleQuoted(node.value.(string))
+		}
+		return jsonString(node.value.(string))
+	}
+	return r.newSrc[node.start:node.end]
+}
+
+func (r *jsRenderer) key(name string) string {
+	if !r.quoteKeys && typeScriptIdentifier.MatchString(name) {
+		return name
+	}
+	return jsonString(name)
+}
+
+func singleQuoted(s string) string {
+	quoted := jsonString(s)
+	body := strings.ReplaceAll(quoted[1:len(quoted)-1], `\"`, `"`)
+	return "'" + strings.ReplaceAll(body, "'", `\'`) + "'"
+}
+
+// startsLine reports whether only whitespace comes before offset on its line
+func startsLine(src string, offset int) bool {
+	lineStart := strings.LastIndexByte(src[:offset], '\n') + 1
+	return strings.TrimSpace(src[lineStart:offset]) == ""
+}
+
+// lineIndent returns the leading whitespace of the line offset is on
+func lineIndent(src string, offset int) string {
+	lineStart := strings.LastIndexByte(src[:offset], '\n') + 1
+	line := src[lineStart:]
+	return line[:len(line)-len(strings.TrimLeft(line, " \t"))]
+}
+
+// detectIndent returns the indentation of one level in node, from the first
+// nested object that has its entries on their own lines
+func detectIndent(src string, node *jsNode) string {
+	if indent := nestedIndent(src, node); indent != "" {
+		return indent
+	}
+	if len(node.props) > 0 && startsLine(src, node.props[0].keyStart) && strings.HasPrefix(lineIndent(src, node.props[0].keyStart), "\t") {
+		return "\t"
+	}
+	return "  "
+}
+
+func nestedIndent(src string, node *jsNode) string {
+	for _, prop := range node.props {
+		child := prop.value
+		if child.kind != "object" || len(child.props) == 0 {
+			continue
+		}
+		outer := lineIndent(src, prop.keyStart)
+		inner := lineIndent(src, child.props[0].keyStart)
+		if startsLine(src, child.props[0].keyStart) && len(inner) > len(outer) && strings.HasPrefix(inner, outer) {
+			return inner[len(outer):]
+		}
+		if indent := nestedIndent(src, child); indent != "" {
+			return indent
+		}
+	}
+	return ""
+}
+
+// prefersQuotedKeys reports whether most keys in node are quoted
+func prefersQuotedKeys(node *jsNode) bool {
+	quoted, bare := 0, 0
+	var count func(node *jsNode)
+	count = func(node *jsNode) {
+		for _, prop := range node.props {
+			if prop.rawKey[0] == '"' || prop.rawKey[0] == '\'' {
+				quoted++
+			} else {
+				bare++
+			}
+			count(prop.value)
+		}
+		for _, item := range node.items {
+			count(item)
+		}
+	}
+	count(node)
+	return quoted >= bare
+}
@@ -0,0 +1,456 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// configFormat reads and writes one kind of basic config. JSON and YAML
+// configs hold the same two things a TS/JS config exports:
+//
+//	{"config": {"name": "...", "project_id": "..."}, "schema": {...}}
+type configFormat struct {
+	// the option init offers for it
+	name       string
+	filename   string
+	extensions []string
+	// readSchema returns the schema as JSON with its keys on the lines they
+	// are on in the config, and the line it starts on
+	readSchema    func(src string) (string, int, error)
+	readProjectID func(src string) (string, error)
+	// writeSchema returns src with its schema replaced by schema, which is
+	// JSON, or with schema added if it has none
+	writeSchema func(src, schema string) (string, error)
+	create      func(name, projectID, schema string) (string, error)
+}
+
+var configFormats = []configFormat{
+	{name: "typescript", filename: "basic.config.ts", extensions: []string{".ts", ".mts"},
+		readSchema: readConfigSchema, readProjectID: readConfigProjectID, writeSchema: writeModuleSchema, create: moduleConfig},
+	{name: "javascript", filename: "basic.config.js", extensions: []string{".js", ".mjs"},
+		readSchema: readConfigSchema, readProjectID: readConfigProjectID, writeSchema: writeModuleSchema, create: moduleConfig},
+	{name: "json", filename: "basic.config.json", extensions: []string{".json"},
+		readSchema: readJSONSchema, readProjectID: readJSONProjectID, writeSchema: writeJSONSchema, create: jsonConfig},
+	{name: "yaml", filename: "basic.config.yaml", extensions: []string{".yaml", ".yml"},
+		readSchema: readYAMLSchema, readProjectID: readYAMLProjectID, writeSchema: writeYAMLSchema, create: yamlConfig},
+}
+
+// configFileNames are the names of basic configs, in the order they're
+// looked for
+var configFileNames = []string{"basic.config.ts", "basic.config.js", "basic.config.json", "basic.config.yaml", "basic.config.yml"}
+
+// configFormatOf returns the format of filename, by its extension
+func configFormatOf(filename string) (configFormat, bool) {
+	ext := strings.ToLower(filepath.Ext(filename))
+	for _, format := range configFormats {
+		for _, e := range format.extensions {
+			if e == ext {
+				return format, true
+			}
+		}
+	}
+	return configFormat{}, false
+}
+
+// configFormatNamed returns the format init offers as name, TypeScript if
+// there's none
+func configFormatNamed(name string) configFormat {
+	for _, format := range configFormats {
+		if format.name == name {
+			return format
+		}
+	}
+	return configFormats[0]
+}
+
+func configFormatNames() []string {
+	names := make([]string, len(configFormats))
+	for i, format := range configFormats {
+		names[i] = format.name
+	}
+	return names
+}
+
+func moduleConfig(name, projectID, schema string) (string, error) {
+	return fmt.Sprintf(`
+// Basic Project Configuration
+// see  the docs for more info: https://docs.basic.tech
+export const config = {
+  name: %s,
+  project_id: %s
+};
+
+export const schema = %s;
+`, jsonString(name), jsonString(projectID), schema), nil
+}
+
+func writeModuleSchema(src, schema string) (string, error) {
+	updated, found, err := replaceConfigSchema(src, schema)
+	if err != nil || found {
+		return updated, err
+	}
+	// the config doesn't declare a schema yet, so add one at the end
+	rendered, err := renderNewSchema(schema)
+	if err != nil {
+		return "", err
+	}
+	return src + "\nexport const schema = " + rendered + ";\n", nil
+}
+
+// isConfigObject tells a JSON or YAML config from a file that only holds a
+// schema, which 'basic validate' and 'basic schema import' also read
+func isConfigObject(hasKey func(string) bool) bool {
+	return hasKey("config") || hasKey("schema")
+}
+
+// readJSONSchema reads the schema of a JSON config, or a JSON file that's
+// only a schema. JSON that doesn't parse is returned as it is, for the
+// validation to point at the syntax error.
+func readJSONSchema(src string) (string, int, error) {
+	p, top, err := parseJSValue(src)
+	if err != nil || top.kind != "object" {
+		return src, 1, nil
+	}
+	if !isConfigObject(func(key string) bool { return top.get(key) != nil }) {
+		return p.toJSON(top), lineOf(src, top.start), nil
+	}
+	schema := top.get("schema")
+	if schema == nil {
+		return "", 0, errNoSchemaFound
+	}
+	return p.toJSON(schema), lineOf(src, schema.start), nil
+}
+
+func readJSONProjectID(src string) (string, error) {
+	_, top, err := parseJSValue(src)
+	if err != nil {
+		return "", err
+	}
+	candidates := []*jsNode{top}
+	if isConfigObject(func(key string) bool { return top.get(key) != nil }) {
+		candidates = []*jsNode{top.get("config"), top.get("schema")}
+	}
+	for _, node := range candidates {
+		if node == nil {
+			continue
+		}
+		if id := node.get("project_id"); id != nil && id.kind == "string" && id.value != "" {
+			return id.value.(string), nil
+		}
+	}
+	return "", nil
+}
+
+// writeJSONSchema replaces the schema of a JSON config the way
+// replaceConfigSchema does for TS/JS, keeping the rest of the file as it is
+func writeJSONSchema(src, schema string) (string, error) {
+	p, top, err := parseJSValue(src)
+	if err != nil {
+		return "", err
+	}
+	if top.kind != "object" {
+		return "", p.errorf(top.start, "the config must be a JSON object")
+	}
+	newParser, updated, err := parseJSValue(schema)
+	if err != nil {
+		return "", fmt.Errorf("invalid schema JSON: %v", err)
+	}
+
+	newTop := updated
+	if isConfigObject(func(key string) bool { return top.get(key) != nil }) {
+		newTop = &jsNode{kind: "object"}
+		found := false
+		for _, prop := range top.props {
+			if prop.key == "schema" {
+				prop = &jsProperty{key: prop.key, value: updated}
+				found = true
+			}
+			newTop.props = append(newTop.props, prop)
+		}
+		if !found {
+			newTop.props = append(newTop.props, &jsProperty{key: "schema", value: updated})
+		}
+	}
+
+	r := &jsRenderer{src: src, newSrc: newParser.src, indent: detectIndent(src, top), quoteKeys: true}
+	return src[:top.start] + r.render(newTop, top, lineIndent(src, top.start)) + src[top.end:], nil
+}
+
+func jsonConfig(name, projectID, schema string) (string, error) {
+	raw := fmt.Sprintf(`{"config": {"name": %s, "project_id": %s}, "schema": %s}`, jsonString(name), jsonString(projectID), schema)
+	var compact, indented bytes.Buffer
+	if err := json.Compact(&compact, []byte(raw)); err != nil {
+		return "", fmt.Errorf("invalid schema JSON: %v", err)
+	}
+	json.Indent(&indented, compact.Bytes(), "", "  ")
+	return indented.String() + "\n", nil
+}
+
+// parseYAMLConfig parses a YAML config into its document and top mapping,
+// which are empty for an empty file
+func parseYAMLConfig(src string) (*yaml.Node, *yaml.Node, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal([]byte(src), &doc); err != nil {
+		return nil, nil, fmt.Errorf("%v", strings.TrimPrefix(err.Error(), "yaml: "))
+	}
+	if len(doc.Content) == 0 {
+		top := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+		return &yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{top}}, top, nil
+	}
+	top := doc.Content[0]
+	if top.Kind != yaml.MappingNode {
+		return nil, nil, fmt.Errorf("line %d: the config must be a mapping", top.Line)
+	}
+	return &doc, top, nil
+}
+
+// yamlGet returns the index of key's value in mapping, or -1
+func yamlGet(mapping *yaml.Node, key string) int {
+	if mapping == nil || mapping.Kind != yaml.MappingNode {
+		return -1
+	}
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			return i + 1
+		}
+	}
+	return -1
+}
+
+func yamlHasKey(mapping *yaml.Node) func(string) bool {
+	return func(key string) bool { return yamlGet(mapping, key) >= 0 }
+}
+
+func readYAMLSchema(src string) (string, int, error) {
+	_, top, err := parseYAMLConfig(src)
+	if err != nil {
+		return "", 0, err
+	}
+	if len(top.Content) == 0 {
+		return "", 0, errNoSchemaFound
+	}
+	schema, line := top, top.Line
+	if isConfigObject(yamlHasKey(top)) {
+		i := yamlGet(top, "schema")
+		if i < 0 {
+			return "", 0, errNoSchemaFound
+		}
+		schema, line = top.Content[i], top.Content[i-1].Line
+	}
+
+	start := line
+	var s strings.Builder
+	if err := writeYAMLAsJSON(&s, schema, &line); err != nil {
+		return "", 0, err
+	}
+	return s.String(), start, nil
+}
+
+// writeYAMLAsJSON writes node as JSON, with every key and item where it is
+// in the YAML, counting the line s is on in line
+func writeYAMLAsJSON(s *strings.Builder, node *yaml.Node, line *int) error {
+	moveTo := func(target *yaml.Node) {
+		if *line >= target.Line {
+			return
+		}
+		for ; *line < target.Line; *line++ {
+			s.WriteString("\n")
+		}
+		s.WriteString(strings.Repeat(" ", max(target.Column-1, 0)))
+	}
+	switch node.Kind {
+	case yaml.AliasNode:
+		return writeYAMLAsJSON(s, node.Alias, line)
+	case yaml.MappingNode:
+		s.WriteString("{")
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			if i > 0 {
+				s.WriteString(", ")
+			}
+			moveTo(node.Content[i])
+			s.WriteString(jsonString(node.Content[i].Value) + ": ")
+			if err := writeYAMLAsJSON(s, node.Content[i+1], line); err != nil {
+				return err
+			}
+		}
+		s.WriteString("}")
+	case yaml.SequenceNode:
+		s.WriteString("[")
+		for i, item := range node.Content {
+			if i > 0 {
+				s.WriteString(", ")
+			}
+			moveTo(item)
+			if err := writeYAMLAsJSON(s, item, line); err != nil {
+				return err
+			}
+		}
+		s.WriteString("]")
+	default:
+		var value interface{}
+		if err := node.Decode(&value); err != nil {
+			return fmt.Errorf("line %d: %v", node.Line, err)
+		}
+		if str, ok := value.(string); ok {
+			s.WriteString(jsonString(str))
+			return nil
+		}
+		out, err := json.Marshal(value)
+		if err != nil {
+			return fmt.Errorf("line %d: %s can't be used in a schema", node.Line, node.Value)
+		}
+		s.Write(out)
+	}
+	return nil
+}
+
+func readYAMLProjectID(src string) (string, error) {
+	_, top, err := parseYAMLConfig(src)
+	if err != nil {
+		return "", err
+	}
+	candidates := []*yaml.Node{top}
+	if isConfigObject(yamlHasKey(top)) {
+		candidates = nil
+		for _, key := range []string{"config", "schema"} {
+			if i := yamlGet(top, key); i >= 0 {
+				candidates = append(candidates, top.Content[i])
+			}
+		}
+	}
+	for _, node := range candidates {
+		if i := yamlGet(node, "project_id"); i >= 0 && node.Content[i].Kind == yaml.ScalarNode && node.Content[i].Value != "" {
+			return node.Content[i].Value, nil
+		}
+	}
+	return "", nil
+}
+
+// writeYAMLSchema replaces the schema of a YAML config. The file is written
+// out again, so its layout is normalized, but the comments stay with the
+// keys they belong to.
+func writeYAMLSchema(src, schema string) (string, error) {
+	doc, top, err := parseYAMLConfig(src)
+	if err != nil {
+		return "", err
+	}
+	updated, err := yamlFromJSON(schema)
+	if err != nil {
+		return "", err
+	}
+
+	if !isConfigObject(yamlHasKey(top)) && len(top.Content) > 0 {
+		// a file that's only a schema
+		doc.Content[0] = mergeYAML(top, updated)
+	} else if i := yamlGet(top, "schema"); i >= 0 {
+		top.Content[i] = mergeYAML(top.Content[i], updated)
+	} else {
+		top.Content = append(top.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: "schema"}, updated)
+	}
+	return encodeYAML(doc, yamlIndent(src))
+}
+
+// yamlFromJSON parses JSON into YAML nodes laid out in block style
+func yamlFromJSON(schema string) (*yaml.Node, error) {
+	// YAML doesn't allow the tabs JSON may be indented with
+	var compact bytes.Buffer
+	if err := json.Compact(&compact, []byte(schema)); err != nil {
+		return nil, fmt.Errorf("invalid schema JSON: %v", err)
+	}
+	var doc yaml.Node
+	if err := yaml.Unmarshal(compact.Bytes(), &doc); err != nil || len(doc.Content) == 0 {
+		return nil, fmt.Errorf("invalid schema JSON: %v", err)
+	}
+	var clearStyle func(node *yaml.Node)
+	clearStyle = func(node *yaml.Node) {
+		node.Style = 0
+		for _, child := range node.Content {
+			clearStyle(child)
+		}
+	}
+	clearStyle(doc.Content[0])
+	return doc.Content[0], nil
+}
+
+// mergeYAML returns updated, keeping the nodes of old that didn't change and
+// so their comments
+func mergeYAML(old, updated *yaml.Node) *yaml.Node {
+	var oldValue, updatedValue interface{}
+	if old.Decode(&oldValue) == nil && updated.Decode(&updatedValue) == nil && reflect.DeepEqual(oldValue, updatedValue) {
+		return old
+	}
+	if old.Kind != yaml.MappingNode || updated.Kind != yaml.MappingNode {
+		updated.HeadComment, updated.LineComment, updated.FootComment = old.HeadComment, old.LineComment, old.FootComment
+		return updated
+	}
+
+	merged := *old
+	merged.Content = nil
+	seen := map[string]bool{}
+	// keep the order of the old mapping, with new keys at the end
+	for i := 0; i+1 < len(old.Content); i += 2 {
+		key := old.Content[i].Value
+		if j := yamlGet(updated, key); j >= 0 {
+			merged.Content = append(merged.Content, old.Content[i], mergeYAML(old.Content[i+1], updated.Content[j]))
+			seen[key] = true
+		}
+	}
+	for i := 0; i+1 < len(updated.Content); i += 2 {
+		if !seen[updated.Content[i].Value] {
+			merged.Content = append(merged.Content, updated.Content[i], updated.Content[i+1])
+		}
+	}
+	return &merged
+}
+
+// yamlIndent returns how many spaces src indents by, 2 if it doesn't
+func yamlIndent(src string) int {
+	for _, line := range strings.Split(src, "\n") {
+		trimmed := strings.TrimLeft(line, " ")
+		if indent := len(line) - len(trimmed); indent > 0 && trimmed != "" && !strings.HasPrefix(trimmed, "#") {
+			return indent
+		}
+	}
+	return 2
+}
+
+func encodeYAML(doc *yaml.Node, indent int) (string, error) {
+	var out bytes.Buffer
+	encoder := yaml.NewEncoder(&out)
+	encoder.SetIndent(indent)
+	if err := encoder.Encode(doc); err != nil {
+		return "", fmt.Errorf("error encoding YAML: %v", err)
+	}
+	encoder.Close()
+	return out.String(), nil
+}
+
+func yamlConfig(name, projectID, schema string) (string, error) {
+	schemaNode, err := yamlFromJSON(schema)
+	if err != nil {
+		return "", err
+	}
+	str := func(value string) *yaml.Node {
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: value}
+	}
+	config := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map", Content: []*yaml.Node{
+		str("name"), str(name),
+		str("project_id"), str(projectID),
+	}}
+	top := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map", Content: []*yaml.Node{
+		str("config"), config,
+		str("schema"), schemaNode,
+	}}
+	doc := &yaml.Node{
+		Kind:        yaml.DocumentNode,
+		HeadComment: "Basic Project Configuration\nsee the docs for more info: https://docs.basic.tech",
+		Content:     []*yaml.Node{top},
+	}
+	return encodeYAML(doc, 2)
+}
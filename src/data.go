@@ -0,0 +1,477 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/atotto/clipboard"
+	"github.com/charmbracelet/bubbles/table"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/huh"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// dataPageSize is how many records 'basic data' fetches at a time, unless
+// --limit is set
+const dataPageSize = 50
+
+// dataField is a column of the data browser
+type dataField struct {
+	name      string
+	fieldType string
+}
+
+type dataSchemaMsg struct {
+	projectID string
+	tables    map[string][]dataField
+	err       error
+}
+
+type dataRecordsMsg struct {
+	records []map[string]interface{}
+	offset  int
+	err     error
+}
+
+// loadDataSchema reads the tables of the project in the current directory
+// from its published schema, checking the table asked for exists
+func loadDataSchema() tea.Msg {
+	projectID, err := configProjectID()
+	if err != nil {
+		return dataSchemaMsg{err: err}
+	}
+
+	schema, err := getProjectSchema(projectID)
+	if err != nil {
+		return dataSchemaMsg{err: fmt.Errorf("error fetching project schema: %v", err)}
+	}
+	if schema == "" {
+		return dataSchemaMsg{err: fmt.Errorf("project %s has no published schema yet, run 'basic push' first", projectID)}
+	}
+	schemaData, err := parseSchemaJSON(schema)
+	if err != nil {
+		return dataSchemaMsg{err: err}
+	}
+
+	tables := schemaDataFields(schemaData)
+	if len(tables) == 0 {
+		return dataSchemaMsg{err: fmt.Errorf("project %s has no tables", projectID)}
+	}
+	if args := positionalArgs(); len(args) > 0 {
+		if _, ok := tables[args[0]]; !ok {
+			return dataSchemaMsg{err: fmt.Errorf("table %s not found. tables in this project: %s", args[0], strings.Join(sortedTableNames(tables), ", "))}
+		}
+	}
+	return dataSchemaMsg{projectID: projectID, tables: tables}
+}
+
+// schemaDataFields returns the fields of every table in schemaData, sorted by name
+func schemaDataFields(schemaData map[string]interface{}) map[string][]dataField {
+	result := map[string][]dataField{}
+	tables, _ := schemaData["tables"].(map[string]interface{})
+	for tableName, tableData := range tables {
+		fieldsData, _ := tableData.(map[string]interface{})["fields"].(map[string]interface{})
+		fields := []dataField{}
+		for fieldName, fieldData := range fieldsData {
+			fieldType, _ := fieldData.(map[string]interface{})["type"].(string)
+			fields = append(fields, dataField{name: fieldName, fieldType: fieldType})
+		}
+		sort.Slice(fields, func(i, j int) bool {
+			return fields[i].name < fields[j].name
+		})
+		result[tableName] = fields
+	}
+	return result
+}
+
+func sortedTableNames(tables map[string][]dataField) []string {
+	names := []string{}
+	for name := range tables {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// getTableRecords fetches up to limit records of a table, starting at offset
+func getTableRecords(projectID, tableName string, limit, offset int) ([]map[string]interface{}, error) {
+	token, err := loadToken()
+	if err != nil || token == nil {
+		return nil, errors.New(loggedOutError(err))
+	}
+
+	requestURL := fmt.Sprintf("https://api.basic.tech/account/%s/db/%s?limit=%d&offset=%d",
+		projectID, url.PathEscape(tableName), limit, offset)
+	resp, err := authClient(token).Get(requestURL)
+	if err != nil {
+		return nil, apiRequestError("error fetching records", requestURL, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("received non-200 response fetching records: %d", resp.StatusCode)
+	}
+
+	var response struct {
+		Data []map[string]interface{} `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return nil, fmt.Errorf("error parsing JSON response: %v", err)
+	}
+	return response.Data, nil
+}
+
+func fetchRecordsCmd(projectID, tableName string, limit, offset int) tea.Cmd {
+	return func() tea.Msg {
+		records, err := getTableRecords(projectID, tableName, limit, offset)
+		return dataRecordsMsg{records: records, offset: offset, err: err}
+	}
+}
+
+func dataLimit() int {
+	if l, err := strconv.Atoi(flagValue("--limit")); err == nil && l > 0 {
+		return l
+	}
+	return dataPageSize
+}
+
+// performDataJSON prints the first page of a table's records for 'basic data <table> --json'
+func performDataJSON() tea.Msg {
+	schemaMsg := loadDataSchema().(dataSchemaMsg)
+	if schemaMsg.err != nil {
+		return errorScreenMsg{errorMessage: schemaMsg.err.Error()}
+	}
+
+	records, err := getTableRecords(schemaMsg.projectID, positionalArgs()[0], dataLimit(), 0)
+	if err != nil {
+		return errorScreenMsg{errorMessage: err.Error()}
+	}
+	if records == nil {
+		records = []map[string]interface{}{}
+	}
+	out, _ := json.MarshalIndent(records, "", "  ")
+	fmt.Println(string(out))
+	return tea.Quit()
+}
+
+// dataTableModel browses the records of one table a page at a time. Columns
+// that don't fit the terminal are scrolled into view with the arrow keys.
+type dataTableModel struct {
+	projectID string
+	tables    map[string][]dataField
+	tableName string
+	// id followed by the table's fields
+	columns []dataField
+	records []map[string]interface{}
+	offset  int
+	limit   int
+	// first column shown
+	colOffset int
+	width     int
+	height    int
+	table     table.Model
+	// the table picker, shown when no table was given and by 't'
+	picker  *huh.Form
+	loading bool
+	err     error
+	// the footer message after copying a record
+	notification string
+}
+
+// openDataBrowser starts on the table given as an argument, or asks which one
+// to browse
+func openDataBrowser(msg dataSchemaMsg) (tea.Model, tea.Cmd) {
+	t := table.New(table.WithFocused(true))
+	t.SetStyles(tableStyles())
+
+	m := dataTableModel{
+		projectID: msg.projectID,
+		tables:    msg.tables,
+		limit:     dataLimit(),
+		table:     t,
+	}
+
+	// the program is already running, so ask for the terminal size again
+	if args := positionalArgs(); len(args) > 0 {
+		m, cmd := m.selectTable(args[0])
+		return m, tea.Batch(cmd, tea.WindowSize())
+	}
+	m.picker = m.newTablePicker()
+	return m, tea.Batch(m.picker.Init(), tea.WindowSize())
+}
+
+func (m dataTableModel) newTablePicker() *huh.Form {
+	options := []huh.Option[string]{}
+	for _, name := range sortedTableNames(m.tables) {
+		options = append(options, huh.NewOption(name, name))
+	}
+	return huh.NewForm(
+		huh.NewGroup(
+			huh.NewSelect[string]().
+				Key("table").
+				Title("Which table do you want to browse?").
+				Options(options...),
+		),
+	).WithShowHelp(false)
+}
+
+func (m dataTableModel) selectTable(tableName string) (dataTableModel, tea.Cmd) {
+	m.tableName = tableName
+	m.columns = []dataField{{name: "id", fieldType: "id"}}
+	for _, f := range m.tables[tableName] {
+		if f.name != "id" {
+			m.columns = append(m.columns, f)
+		}
+	}
+	m.colOffset = 0
+	m.records = nil
+	m.notification = ""
+	return m.loadPage(0)
+}
+
+func (m dataTableModel) loadPage(offset int) (dataTableModel, tea.Cmd) {
+	m.loading = true
+	m.err = nil
+	return m, fetchRecordsCmd(m.projectID, m.tableName, m.limit, offset)
+}
+
+func (m dataTableModel) Init() tea.Cmd {
+	return nil
+}
+
+func (m dataTableModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if m.picker != nil {
+		return m.updatePicker(msg)
+	}
+
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		m.refreshTable()
+		return m, nil
+	case dataRecordsMsg:
+		m.loading = false
+		if msg.err != nil {
+			m.err = msg.err
+			return m, nil
+		}
+		if len(msg.records) == 0 && msg.offset > 0 {
+			m.notification = "no more records"
+			return m, nil
+		}
+		m.notification = ""
+		m.records = msg.records
+		m.offset = msg.offset
+		m.refreshTable()
+		m.table.SetCursor(0)
+		return m, nil
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "ctrl+c", "esc", "q":
+			return m, tea.Quit
+		case "n":
+			// a short page is the last one
+			if !m.loading && len(m.records) == m.limit {
+				return m.loadPage(m.offset + m.limit)
+			}
+			return m, nil
+		case "p":
+			if !m.loading && m.offset > 0 {
+				return m.loadPage(max(m.offset-m.limit, 0))
+			}
+			return m, nil
+		case "left", "h":
+			if m.colOffset > 0 {
+				m.colOffset--
+				m.refreshTable()
+			}
+			return m, nil
+		case "right", "l":
+			if m.colOffset < len(m.columns)-1 {
+				m.colOffset++
+				m.refreshTable()
+			}
+			return m, nil
+		case "t":
+			if len(m.tables) > 1 {
+				m.picker = m.newTablePicker()
+				return m, m.picker.Init()
+			}
+			return m, nil
+		case "c":
+			if cursor := m.table.Cursor(); cursor >= 0 && cursor < len(m.records) {
+				out, _ := json.MarshalIndent(m.records[cursor], "", "  ")
+				clipboard.WriteAll(string(out))
+				m.notification = "record copied to clipboard as JSON!"
+			}
+			return m, nil
+		}
+	}
+
+	var cmd tea.Cmd
+	m.table, cmd = m.table.Update(msg)
+	return m, cmd
+}
+
+// updatePicker runs the table picker. esc goes back to the current table, or
+// quits if none has been picked yet.
+func (m dataTableModel) updatePicker(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "ctrl+c":
+			return m, tea.Quit
+		case "esc":
+			if m.tableName == "" {
+				return m, tea.Quit
+			}
+			m.picker = nil
+			return m, nil
+		}
+	}
+
+	form, cmd := m.picker.Update(msg)
+	if f, ok := form.(*huh.Form); ok {
+		m.picker = f
+		switch f.State {
+		case huh.StateCompleted:
+			m.picker = nil
+			return m.selectTable(f.GetString("table"))
+		case huh.StateAborted:
+			return m, tea.Quit
+		}
+	}
+	return m, cmd
+}
+
+// dataColumnWidth sizes a column by its field type, never narrower than its name
+func dataColumnWidth(f dataField) int {
+	width := 16
+	switch f.fieldType {
+	case "id":
+		width = 36
+	case "string":
+		width = 24
+	case "number":
+		width = 12
+	case "boolean":
+		width = 7
+	case "json":
+		width = 30
+	}
+	return max(width, len(f.name))
+}
+
+// visibleColumns returns the columns from colOffset on that fit the terminal,
+// always at least one
+func (m dataTableModel) visibleColumns() []dataField {
+	const cellPadding = 2
+	visible := []dataField{}
+	used := 0
+	for _, c := range m.columns[m.colOffset:] {
+		width := dataColumnWidth(c) + cellPadding
+		if m.width > 0 && len(visible) > 0 && used+width > m.width {
+			break
+		}
+		visible = append(visible, c)
+		used += width
+	}
+	return visible
+}
+
+func (m *dataTableModel) refreshTable() {
+	if len(m.columns) == 0 {
+		return
+	}
+
+	visible := m.visibleColumns()
+	columns := []table.Column{}
+	for _, c := range visible {
+		columns = append(columns, table.Column{Title: c.name, Width: dataColumnWidth(c)})
+	}
+
+	rows := []table.Row{}
+	for _, record := range m.records {
+		row := table.Row{}
+		for _, c := range visible {
+			row = append(row, formatDataCell(record[c.name]))
+		}
+		rows = append(rows, row)
+	}
+
+	// rows must never have fewer cells than there are columns
+	m.table.SetRows(nil)
+	m.table.SetColumns(columns)
+	m.table.SetRows(rows)
+
+	height := len(rows) + 1
+	if m.height > 0 {
+		height = min(height, m.height-8)
+	}
+	m.table.SetHeight(max(height, 3))
+}
+
+// formatDataCell renders a record value on a single line
+func formatDataCell(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return strings.ReplaceAll(v, "\n", " ")
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	default:
+		return compactJSON(v)
+	}
+}
+
+func (m dataTableModel) View() string {
+	if m.picker != nil {
+		return "\n" + m.picker.View() + "\n"
+	}
+
+	muted := lipgloss.NewStyle().Foreground(lipgloss.Color("240"))
+
+	var s strings.Builder
+	s.WriteString(fmt.Sprintf("\nTable %s", m.tableName))
+	if len(m.records) > 0 {
+		s.WriteString(fmt.Sprintf(" • records %d-%d", m.offset+1, m.offset+len(m.records)))
+	}
+	if visible := len(m.visibleColumns()); visible < len(m.columns) {
+		s.WriteString(fmt.Sprintf(" • columns %d-%d of %d", m.colOffset+1, m.colOffset+visible, len(m.columns)))
+	}
+	s.WriteString("\n\n")
+
+	switch {
+	case m.err != nil:
+		s.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color("9")).Render(fmt.Sprintf("Error: %v", m.err)) + "\n")
+	case m.loading:
+		s.WriteString("Loading records...\n")
+	case len(m.records) == 0 && m.offset == 0:
+		s.WriteString("No records in this table yet.\n")
+	default:
+		s.WriteString(m.table.View() + "\n")
+	}
+
+	if m.notification != "" {
+		s.WriteString("\n" + lipgloss.NewStyle().Foreground(lipgloss.Color("57")).Render(m.notification))
+	}
+	help := "n/p next/previous page • ←/→ scroll columns • 'c' to copy record"
+	if len(m.tables) > 1 {
+		help += " • 't' to switch table"
+	}
+	s.WriteString("\n" + muted.Render(help+" • 'q' to quit") + "\n")
+	return s.String()
+}
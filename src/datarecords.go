@@ -0,0 +1,289 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"golang.org/x/oauth2"
+)
+
+const dataRecordUsage = `Usage:
+  basic data insert <table> [json]
+  basic data get <table> <id>
+  basic data update <table> <id> [json]
+  basic data delete <table> <id>
+
+The JSON can also come from --file <path> or stdin (--stdin or -). Without
+any, insert and update open the record in $EDITOR.`
+
+// isDataRecordCommand reports whether 'basic data' was given a record
+// subcommand rather than a table to browse
+func isDataRecordCommand() bool {
+	args := positionalArgs()
+	if len(args) == 0 {
+		return false
+	}
+	switch args[0] {
+	case "insert", "get", "update", "delete":
+		return true
+	}
+	return false
+}
+
+// recordEditMsg opens initial in the user's editor and saves what they write
+type recordEditMsg struct {
+	initial string
+	save    func(payload string) tea.Msg
+}
+
+type recordEditedMsg struct {
+	payload string
+	save    func(payload string) tea.Msg
+}
+
+// performDataRecord implements 'basic data insert/get/update/delete'
+func performDataRecord() tea.Msg {
+	args := positionalArgs()
+	action := args[0]
+
+	required := 3 // action, table and id
+	if action == "insert" {
+		required = 2
+	}
+	if len(args) < required {
+		return usageMsg{text: dataRecordUsage}
+	}
+	tableName := args[1]
+	var id string
+	if required == 3 {
+		id = args[2]
+	}
+
+	token, err := loadToken()
+	if err != nil || token == nil {
+		return errorScreenMsg{errorMessage: loggedOutError(err)}
+	}
+	projectID, err := configProjectID()
+	if err != nil {
+		return errorScreenMsg{errorMessage: err.Error()}
+	}
+
+	switch action {
+	case "get":
+		record, err := dataRecordRequest(token, http.MethodGet, projectID, tableName, id, nil)
+		if err != nil {
+			return errorScreenMsg{errorMessage: err.Error()}
+		}
+		printRecord(record)
+		return tea.Quit()
+
+	case "delete":
+		remove := func() tea.Msg {
+			if _, err := dataRecordRequest(token, http.MethodDelete, projectID, tableName, id, nil); err != nil {
+				return errorScreenMsg{errorMessage: err.Error()}
+			}
+			if hasFlag("--json") {
+				printRecord(map[string]interface{}{"id": id, "deleted": true})
+			} else {
+				fmt.Printf("Deleted record %s from %s\n", id, tableName)
+			}
+			return tea.Quit()
+		}
+		if assumeYes() {
+			return remove()
+		}
+		if !isInteractive() {
+			return errorScreenMsg{errorMessage: "deleting a record can't be undone, pass --yes to confirm"}
+		}
+		return confirmMsg{
+			title:       fmt.Sprintf("Delete record %s from %s?", id, tableName),
+			description: "This can't be undone.",
+			affirmative: "Yes, delete it",
+			run:         remove,
+			cancelled:   "Delete cancelled",
+		}
+	}
+
+	// insert and update
+	method := http.MethodPost
+	if action == "update" {
+		method = http.MethodPatch
+	}
+	save := func(payload string) tea.Msg {
+		value, err := parseRecordJSON(payload)
+		if err != nil {
+			return errorScreenMsg{errorMessage: err.Error(), code: "usage"}
+		}
+		record, err := dataRecordRequest(token, method, projectID, tableName, id, value)
+		if err != nil {
+			return errorScreenMsg{errorMessage: err.Error()}
+		}
+		printRecord(record)
+		return tea.Quit()
+	}
+
+	payload, err := recordPayload(args[required:])
+	if err != nil {
+		return errorScreenMsg{errorMessage: err.Error()}
+	}
+	if payload != "" {
+		return save(payload)
+	}
+	if !isInteractive() {
+		return usageMsg{text: dataRecordUsage}
+	}
+
+	// start from the current record when updating
+	initial := "{\n  \n}\n"
+	if action == "update" {
+		record, err := dataRecordRequest(token, http.MethodGet, projectID, tableName, id, nil)
+		if err != nil {
+			return errorScreenMsg{errorMessage: err.Error()}
+		}
+		delete(record, "id")
+		out, _ := json.MarshalIndent(record, "", "  ")
+		initial = string(out) + "\n"
+	}
+	return recordEditMsg{initial: initial, save: save}
+}
+
+// recordPayload returns the record JSON given as an argument, with --file or
+// on stdin, or "" if there's none
+func recordPayload(args []string) (string, error) {
+	if len(args) > 0 {
+		return args[0], nil
+	}
+	if filename := flagValue("--file"); filename != "" {
+		content, err := os.ReadFile(filename)
+		if err != nil {
+			return "", fmt.Errorf("error reading %s: %v", filename, err)
+		}
+		return string(content), nil
+	}
+	if hasFlag("--stdin", "-") {
+		content, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return "", fmt.Errorf("error reading stdin: %v", err)
+		}
+		return string(content), nil
+	}
+	return "", nil
+}
+
+func parseRecordJSON(payload string) (map[string]interface{}, error) {
+	var value map[string]interface{}
+	if err := json.Unmarshal([]byte(payload), &value); err != nil {
+		return nil, fmt.Errorf("record must be a JSON object: %v", err)
+	}
+	return value, nil
+}
+
+// dataRecordRequest calls the project data API for one record, or for the
+// table when inserting. value is sent as the record's new fields.
+func dataRecordRequest(token *oauth2.Token, method, projectID, tableName, id string, value map[string]interface{}) (map[string]interface{}, error) {
+	requestURL := fmt.Sprintf("https://api.basic.tech/account/%s/db/%s", projectID, url.PathEscape(tableName))
+	if id != "" {
+		requestURL += "/" + url.PathEscape(id)
+	}
+
+	var body io.Reader
+	if value != nil {
+		jsonBody, err := json.Marshal(map[string]interface{}{"value": value})
+		if err != nil {
+			return nil, fmt.Errorf("error encoding record: %v", err)
+		}
+		body = bytes.NewReader(jsonBody)
+	}
+
+	req, err := http.NewRequest(method, requestURL, body)
+	if err != nil {
+		return nil, fmt.Errorf("error creating request: %v", err)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := authClient(token).Do(req)
+	if err != nil {
+		return nil, apiRequestError("error calling the data API", requestURL, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound && id != "" {
+		return nil, fmt.Errorf("record %s not found in %s", id, tableName)
+	}
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusNoContent {
+		respBody, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("received non-200 response from the data API: %d - %s", resp.StatusCode, string(respBody))
+	}
+	if method == http.MethodDelete {
+		return nil, nil
+	}
+
+	var response struct {
+		Data map[string]interface{} `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return nil, fmt.Errorf("error parsing JSON response: %v", err)
+	}
+	return response.Data, nil
+}
+
+func printRecord(record map[string]interface{}) {
+	out, _ := json.MarshalIndent(record, "", "  ")
+	fmt.Println(string(out))
+}
+
+// editorCommand is $VISUAL or $EDITOR, falling back to vi (notepad on Windows)
+func editorCommand() []string {
+	for _, env := range []string{"VISUAL", "EDITOR"} {
+		if editor := strings.Fields(os.Getenv(env)); len(editor) > 0 {
+			return editor
+		}
+	}
+	if runtime.GOOS == "windows" {
+		return []string{"notepad"}
+	}
+	return []string{"vi"}
+}
+
+// editRecord hands the terminal to the editor and comes back with what was
+// saved. Saving the record unchanged or empty cancels.
+func editRecord(msg recordEditMsg) tea.Cmd {
+	file, err := os.CreateTemp("", "basic-record-*.json")
+	if err != nil {
+		return func() tea.Msg {
+			return errorScreenMsg{errorMessage: fmt.Sprintf("error creating temp file: %v", err)}
+		}
+	}
+	file.WriteString(msg.initial)
+	file.Close()
+
+	editor := editorCommand()
+	cmd := exec.Command(editor[0], append(editor[1:], file.Name())...)
+	return tea.ExecProcess(cmd, func(err error) tea.Msg {
+		defer os.Remove(file.Name())
+		if err != nil {
+			return errorScreenMsg{errorMessage: fmt.Sprintf("error running %s: %v", editor[0], err)}
+		}
+
+		content, err := os.ReadFile(file.Name())
+		if err != nil {
+			return errorScreenMsg{errorMessage: fmt.Sprintf("error reading edited record: %v", err)}
+		}
+		if strings.TrimSpace(string(content)) == strings.TrimSpace(msg.initial) || strings.TrimSpace(string(content)) == "" {
+			fmt.Println("Record unchanged, nothing saved")
+			return tea.Quit()
+		}
+		return recordEditedMsg{payload: string(content), save: msg.save}
+	})
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// performDiff prints the field-level differences between the local schema and
+// the remote one, or them as JSON with --json
+func performDiff() tea.Msg {
+	schema, err := readLocalSchema()
+	if err != nil {
+		return errorScreenMsg{errorMessage: fmt.Sprintf("error reading schema: %v", err)}
+	}
+	localSchema, err := parseSchemaJSON(schema)
+	if err != nil {
+		return errorScreenMsg{errorMessage: err.Error(), code: "schema"}
+	}
+
+	projectID, ok := localSchema["project_id"].(string)
+	if !ok {
+		return errorScreenMsg{errorMessage: "no project ID found in schema", code: "schema"}
+	}
+
+	remote, err := getProjectSchema(projectID)
+	if err != nil {
+		return errorScreenMsg{errorMessage: fmt.Sprintf("error fetching remote schema: %v", err)}
+	}
+	if remote == "" {
+		return errorScreenMsg{errorMessage: "no remote schema found for project " + projectID, code: "not_found"}
+	}
+	remoteSchema, err := parseSchemaJSON(remote)
+	if err != nil {
+		return errorScreenMsg{errorMessage: err.Error()}
+	}
+
+	changes := detectRenamedFields(filterIgnoredChanges(diffSchemas(remoteSchema, localSchema), schemaIgnorePaths()))
+
+	if hasFlag("--json") {
+		if changes == nil {
+			changes = []schemaChange{}
+		}
+		out, _ := json.MarshalIndent(changes, "", "  ")
+		fmt.Println(string(out))
+		return tea.Quit()
+	}
+
+	remoteVersion, _ := readSchemaVersion(remoteSchema)
+	if len(changes) == 0 {
+		fmt.Printf("Local schema matches remote version %.0f.\n", remoteVersion)
+		return tea.Quit()
+	}
+
+	fmt.Printf("Local schema vs remote version %.0f (+ only in local, - only in remote):\n\n", remoteVersion)
+	for _, c := range changes {
+		fmt.Println("  " + renderSchemaChange(c))
+	}
+	fmt.Printf("\n%s in %s\n", countNoun(len(changes), "change"), countNoun(len(changedTables(changes)), "table"))
+	return tea.Quit()
+}
+
+// renderSchemaChange colors a change like git diff does
+func renderSchemaChange(c schemaChange) string {
+	var color string
+	switch c.Kind {
+	case "added":
+		color = "2"
+	case "removed":
+		color = "9"
+	case "renamed":
+		color = "6"
+	default:
+		color = "3"
+	}
+	return lipgloss.NewStyle().Foreground(lipgloss.Color(color)).Render(formatSchemaChange(c))
+}
@@ -0,0 +1,131 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// maxClockSkew is how far the local clock may drift from the API's before
+// token expiry checks become unreliable
+const maxClockSkew = 60 * time.Second
+
+// doctorResult is the outcome of one 'basic doctor' check
+type doctorResult struct {
+	name   string
+	status string // "ok", "warn" or "fail"
+	detail string
+}
+
+// doctorMsg carries the results of all checks
+type doctorMsg struct {
+	results []doctorResult
+}
+
+func performDoctor() tea.Msg {
+	return doctorMsg{results: []doctorResult{
+		checkClockSkew(),
+		checkLogin(),
+		checkConfig(),
+	}}
+}
+
+// checkClockSkew compares the local clock with the Date header of an API
+// response. Tokens are judged expired by the local clock, so a skewed clock
+// makes valid tokens look expired or lets expired ones through.
+func checkClockSkew() doctorResult {
+	result := doctorResult{name: "Clock"}
+
+	url := "https://api.basic.tech/"
+	resp, err := apiClient().Get(url)
+	if err != nil {
+		result.status = "warn"
+		result.detail = fmt.Sprintf("could not reach the API to compare clocks: %v", err)
+		return result
+	}
+	resp.Body.Close()
+
+	serverTime, err := http.ParseTime(resp.Header.Get("Date"))
+	if err != nil {
+		result.status = "warn"
+		result.detail = "the API response has no usable Date header to compare clocks with"
+		return result
+	}
+
+	// the Date header only has second precision
+	skew := time.Since(serverTime).Round(time.Second)
+	direction := "ahead of"
+	if skew < 0 {
+		skew = -skew
+		direction = "behind"
+	}
+	if skew > maxClockSkew {
+		result.status = "warn"
+		result.detail = fmt.Sprintf("your clock is %s %s the API's, so tokens may look expired when they aren't (or the other way around). Sync your system clock", shortDuration(skew), direction)
+		return result
+	}
+
+	result.status = "ok"
+	result.detail = fmt.Sprintf("in sync with the API (within %s)", shortDuration(maxClockSkew))
+	return result
+}
+
+func checkLogin() doctorResult {
+	result := doctorResult{name: "Login"}
+
+	token, err := readStoredToken()
+	if err != nil || token == nil {
+		result.status = "fail"
+		result.detail = loggedOutError(err)
+		return result
+	}
+	if token.RefreshToken == "" {
+		result.status = "warn"
+		result.detail = errNoRefreshToken.Error()
+		return result
+	}
+
+	result.status = "ok"
+	result.detail = fmt.Sprintf("logged in to profile %s, access token %s", activeProfile(), tokenExpiresIn(token.Expiry))
+	if account, ok := loadAccountInfo(); ok && account.Email != "" {
+		result.detail = fmt.Sprintf("logged in as %s to profile %s, access token %s", account.Email, activeProfile(), tokenExpiresIn(token.Expiry))
+	}
+	return result
+}
+
+func checkConfig() doctorResult {
+	result := doctorResult{name: "Config"}
+
+	filename, err := findConfigFile()
+	if err != nil {
+		result.status = "warn"
+		result.detail = "no basic config in this directory"
+		return result
+	}
+	if _, err := readSchemaFromConfig(); err != nil {
+		result.status = "fail"
+		result.detail = fmt.Sprintf("%s: %v", filename, err)
+		return result
+	}
+
+	result.status = "ok"
+	result.detail = filename
+	return result
+}
+
+// formatDoctorResult renders a check as a single line, e.g. "✓ Clock: in sync with the API"
+func formatDoctorResult(r doctorResult) string {
+	var icon string
+	switch r.status {
+	case "ok":
+		icon = lipgloss.NewStyle().Foreground(lipgloss.Color("2")).Render("✓")
+	case "warn":
+		icon = lipgloss.NewStyle().Foreground(lipgloss.Color("3")).Render("!")
+	default:
+		icon = lipgloss.NewStyle().Foreground(lipgloss.Color("9")).Render("✗")
+	}
+	return fmt.Sprintf("%s %s: %s", icon, r.name, r.detail)
+}
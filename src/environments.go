@@ -0,0 +1,175 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// environmentsFile maps environment names to projects. It sits next to the
+// config so it can be committed with it.
+var environmentsFile = filepath.Join(".basic", "environments.json")
+
+const envUsage = `Usage:
+  basic env list
+  basic env add <name> <project_id>
+  basic env remove <name>
+
+Then use --env <name> with status, push and pull.`
+
+var environmentNamePattern = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+type environment struct {
+	ProjectID string `json:"project_id"`
+}
+
+type environmentsConfig struct {
+	Environments map[string]environment `json:"environments"`
+}
+
+func loadEnvironments() (map[string]environment, error) {
+	data, err := os.ReadFile(environmentsFile)
+	if os.IsNotExist(err) {
+		return map[string]environment{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error reading %s: %v", environmentsFile, err)
+	}
+
+	var config environmentsConfig
+	if err := json.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("error parsing %s: %v", environmentsFile, err)
+	}
+	if config.Environments == nil {
+		config.Environments = map[string]environment{}
+	}
+	return config.Environments, nil
+}
+
+func saveEnvironments(environments map[string]environment) error {
+	if err := os.MkdirAll(filepath.Dir(environmentsFile), 0755); err != nil {
+		return fmt.Errorf("error creating %s: %v", filepath.Dir(environmentsFile), err)
+	}
+	data, err := json.MarshalIndent(environmentsConfig{Environments: environments}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error encoding environments: %v", err)
+	}
+	if err := os.WriteFile(environmentsFile, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("error writing %s: %v", environmentsFile, err)
+	}
+	return nil
+}
+
+func sortedEnvironmentNames(environments map[string]environment) []string {
+	names := make([]string, 0, len(environments))
+	for name := range environments {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// environmentProject returns the project of the environment called name
+func environmentProject(name string) (string, error) {
+	environments, err := loadEnvironments()
+	if err != nil {
+		return "", err
+	}
+	env, ok := environments[name]
+	if !ok {
+		if len(environments) == 0 {
+			return "", fmt.Errorf("unknown environment %q, add it with 'basic env add %s <project_id>'", name, name)
+		}
+		return "", fmt.Errorf("unknown environment %q, known environments: %s", name, strings.Join(sortedEnvironmentNames(environments), ", "))
+	}
+	return env.ProjectID, nil
+}
+
+// checkEnvironmentFlag makes sure --env names a known environment before any
+// command acts on it, so a typo never falls back to the config's project
+func checkEnvironmentFlag() error {
+	name := flagValue("--env")
+	if name == "" {
+		return nil
+	}
+	if flagValue("--project") != "" {
+		return fmt.Errorf("--project and --env both pick the project, use one of them")
+	}
+	_, err := environmentProject(name)
+	return err
+}
+
+// performEnv implements 'basic env list/add/remove'
+func performEnv() tea.Msg {
+	args := positionalArgs()
+	action := "list"
+	if len(args) > 0 {
+		action = args[0]
+	}
+
+	environments, err := loadEnvironments()
+	if err != nil {
+		return errorScreenMsg{errorMessage: err.Error()}
+	}
+
+	switch action {
+	case "list":
+		if hasFlag("--json") {
+			out, _ := json.MarshalIndent(environments, "", "  ")
+			fmt.Println(string(out))
+			return tea.Quit()
+		}
+		if len(environments) == 0 {
+			fmt.Println("No environments yet. Add one with 'basic env add <name> <project_id>'")
+			return tea.Quit()
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "ENVIRONMENT\tPROJECT")
+		for _, name := range sortedEnvironmentNames(environments) {
+			fmt.Fprintf(w, "%s\t%s\n", name, describeProject(environments[name].ProjectID, false))
+		}
+		w.Flush()
+		return tea.Quit()
+	case "add":
+		if len(args) < 3 {
+			return usageMsg{text: envUsage}
+		}
+		name, projectID := args[1], args[2]
+		if !environmentNamePattern.MatchString(name) {
+			return errorScreenMsg{errorMessage: fmt.Sprintf("invalid environment name %q: use letters, digits, - and _", name), code: "usage"}
+		}
+		_, existed := environments[name]
+		environments[name] = environment{ProjectID: projectID}
+		if err := saveEnvironments(environments); err != nil {
+			return errorScreenMsg{errorMessage: err.Error()}
+		}
+		verb := "Added"
+		if existed {
+			verb = "Updated"
+		}
+		fmt.Printf("%s environment %s: %s\n", verb, name, describeProject(projectID, false))
+		return tea.Quit()
+	case "remove":
+		if len(args) < 2 {
+			return usageMsg{text: envUsage}
+		}
+		name := args[1]
+		if _, ok := environments[name]; !ok {
+			return errorScreenMsg{errorMessage: fmt.Sprintf("unknown environment %q", name)}
+		}
+		delete(environments, name)
+		if err := saveEnvironments(environments); err != nil {
+			return errorScreenMsg{errorMessage: err.Error()}
+		}
+		fmt.Printf("Removed environment %s\n", name)
+		return tea.Quit()
+	}
+	return usageMsg{text: envUsage}
+}
@@ -0,0 +1,302 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const generateUsage = `Usage: basic generate <target>
+
+Targets:
+  types - Write TypeScript interfaces for every table to basic.types.ts (--out <file>, --watch)
+  zod - Write Zod schemas for every table to basic.zod.ts (--out <file>, --watch)
+`
+
+// codeGenerator turns the schema's tables into a source file for the app
+type codeGenerator struct {
+	defaultOut string
+	generate   func(tables []generatedTable, configFile string) string
+}
+
+var codeGenerators = map[string]codeGenerator{
+	"types": {defaultOut: "basic.types.ts", generate: typeScriptTypes},
+	"zod":   {defaultOut: "basic.zod.ts", generate: zodSchemas},
+}
+
+// generatedTable is a table as the generators see it, sorted and named
+type generatedTable struct {
+	name string
+	// the table's name in PascalCase, unique among the tables
+	typeName string
+	fields   []generatedField
+}
+
+type generatedField struct {
+	name      string
+	fieldType string
+	required  bool
+}
+
+// TypeScript types of the basic field types
+var typeScriptFieldTypes = map[string]string{
+	"string":  "string",
+	"number":  "number",
+	"boolean": "boolean",
+	"json":    "Json",
+}
+
+var typeScriptIdentifier = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*$`)
+
+func (m model) runGenerateCommand() (tea.Model, tea.Cmd) {
+	args := positionalArgs()
+	if len(args) == 0 {
+		return m, usage(strings.TrimSuffix(generateUsage, "\n"))
+	}
+	target := args[0]
+	generator, ok := codeGenerators[target]
+	if !ok {
+		return m, usage(fmt.Sprintf("Unknown target %q\n\n%s", target, strings.TrimSuffix(generateUsage, "\n")))
+	}
+
+	configFile := flagValue("--file")
+	if configFile == "" {
+		var err error
+		configFile, err = findConfigFile()
+		if err != nil {
+			return m, func() tea.Msg {
+				return errorScreenMsg{errorMessage: err.Error()}
+			}
+		}
+	}
+	out := flagValue("--out")
+	if out == "" {
+		out = generator.defaultOut
+	}
+
+	if hasFlag("--watch") {
+		m.state = stateWatching
+		m.watchHeader = fmt.Sprintf("Regenerating %s on every save of %s...", out, configFile)
+		return m, runWatchCheck(configFile, generateWatchCheck(generator, out), false)
+	}
+	return m, func() tea.Msg {
+		message, err := generateFile(generator, configFile, out)
+		if err != nil {
+			return errorScreenMsg{errorMessage: err.Error()}
+		}
+		fmt.Println(message)
+		return tea.Quit()
+	}
+}
+
+// generateFile writes what generator makes of configFile's schema to out. An
+// unchanged file isn't touched, so tools watching it don't rebuild for nothing.
+func generateFile(generator codeGenerator, configFile, out string) (string, error) {
+	schema, err := readSchemaFromFile(configFile)
+	if err != nil {
+		return "", fmt.Errorf("error reading schema: %v", err)
+	}
+	schemaData, err := parseSchemaJSON(schema)
+	if err != nil {
+		return "", err
+	}
+
+	tables := generatorTables(schemaData)
+	content := []byte(generator.generate(tables, configFile))
+	if existing, err := os.ReadFile(out); err == nil && bytes.Equal(existing, content) {
+		return fmt.Sprintf("%s is up to date (%s)", out, countNoun(len(tables), "table")), nil
+	}
+	if err := os.WriteFile(out, content, 0644); err != nil {
+		return "", fmt.Errorf("error writing %s: %v", out, err)
+	}
+	return fmt.Sprintf("Wrote %s (%s)", out, countNoun(len(tables), "table")), nil
+}
+
+func generateWatchCheck(generator codeGenerator, out string) watchCheck {
+	return func(filename string) []string {
+		lines := []string{"", fmt.Sprintf("[%s] %s", time.Now().Format("15:04:05"), filename)}
+		message, err := generateFile(generator, filename, out)
+		if err != nil {
+			return append(lines, fmt.Sprintf("Error: %v", err))
+		}
+		return append(lines, message)
+	}
+}
+
+// generatorTables reads the tables of a schema for the generators. Every
+// record has an id, so tables that don't declare one get it as a string.
+func generatorTables(schemaData map[string]interface{}) []generatedTable {
+	tablesData, _ := schemaData["tables"].(map[string]interface{})
+	names := interfaceNames(tablesData)
+
+	tables := []generatedTable{}
+	for _, name := range sortedKeys(tablesData) {
+		tableData, _ := tablesData[name].(map[string]interface{})
+		fieldsData, _ := tableData["fields"].(map[string]interface{})
+
+		table := generatedTable{name: name, typeName: names[name]}
+		if _, ok := fieldsData["id"]; !ok {
+			table.fields = append(table.fields, generatedField{name: "id", fieldType: "string", required: true})
+		}
+		for _, field := range sortedKeys(fieldsData) {
+			fieldData, _ := fieldsData[field].(map[string]interface{})
+			fieldType, _ := fieldData["type"].(string)
+			required, _ := fieldData["required"].(bool)
+			table.fields = append(table.fields, generatedField{name: field, fieldType: fieldType, required: required || field == "id"})
+		}
+		tables = append(tables, table)
+	}
+	return tables
+}
+
+// usesFieldType reports whether any table has a field of fieldType
+func usesFieldType(tables []generatedTable, fieldType string) bool {
+	for _, table := range tables {
+		for _, field := range table.fields {
+			if field.fieldType == fieldType {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func generatedHeader(target, configFile string) string {
+	return fmt.Sprintf("// Generated by 'basic generate %s' from %s, do not edit.\n", target, configFile) +
+		"// Run it again after changing the schema.\n"
+}
+
+const typeScriptJSONType = "export type Json = string | number | boolean | null | Json[] | { [key: string]: Json };\n"
+
+// typeScriptTypes declares an interface per table, named after the table in
+// PascalCase, and a Tables interface mapping table names to them
+func typeScriptTypes(tables []generatedTable, configFile string) string {
+	var s strings.Builder
+	s.WriteString(generatedHeader("types", configFile))
+	if usesFieldType(tables, "json") {
+		s.WriteString("\n" + typeScriptJSONType)
+	}
+
+	for _, table := range tables {
+		fmt.Fprintf(&s, "\nexport interface %s {\n", table.typeName)
+		for _, field := range table.fields {
+			tsType, ok := typeScriptFieldTypes[field.fieldType]
+			if !ok {
+				tsType = "unknown"
+			}
+			optional := "?"
+			if field.required {
+				optional = ""
+			}
+			fmt.Fprintf(&s, "  %s%s: %s;\n", typeScriptKey(field.name), optional, tsType)
+		}
+		s.WriteString("}\n")
+	}
+
+	s.WriteString("\nexport interface Tables {\n")
+	for _, table := range tables {
+		fmt.Fprintf(&s, "  %s: %s;\n", typeScriptKey(table.name), table.typeName)
+	}
+	s.WriteString("}\n\nexport type TableName = keyof Tables;\n")
+	return s.String()
+}
+
+// Zod schemas of the basic field types
+var zodFieldTypes = map[string]string{
+	"string":  "z.string()",
+	"number":  "z.number()",
+	"boolean": "z.boolean()",
+	"json":    "jsonSchema",
+}
+
+// zodSchemas declares a Zod object schema per table, with the type it infers,
+// and a tableSchemas object mapping table names to them
+func zodSchemas(tables []generatedTable, configFile string) string {
+	var s strings.Builder
+	s.WriteString(generatedHeader("zod", configFile))
+	s.WriteString("\nimport { z } from \"zod\";\n")
+	if usesFieldType(tables, "json") {
+		s.WriteString("\n" + typeScriptJSONType)
+		s.WriteString("export const jsonSchema: z.ZodType<Json> = z.lazy(() =>\n" +
+			"  z.union([z.string(), z.number(), z.boolean(), z.null(), z.array(jsonSchema), z.record(z.string(), jsonSchema)])\n);\n")
+	}
+
+	for _, table := range tables {
+		fmt.Fprintf(&s, "\nexport const %sSchema = z.object({\n", table.typeName)
+		for _, field := range table.fields {
+			zodType, ok := zodFieldTypes[field.fieldType]
+			if !ok {
+				zodType = "z.unknown()"
+			}
+			if !field.required {
+				zodType += ".optional()"
+			}
+			fmt.Fprintf(&s, "  %s: %s,\n", typeScriptKey(field.name), zodType)
+		}
+		s.WriteString("});\n")
+		fmt.Fprintf(&s, "export type %s = z.infer<typeof %sSchema>;\n", table.typeName, table.typeName)
+	}
+
+	s.WriteString("\nexport const tableSchemas = {\n")
+	for _, table := range tables {
+		fmt.Fprintf(&s, "  %s: %sSchema,\n", typeScriptKey(table.name), table.typeName)
+	}
+	s.WriteString("} as const;\n\nexport type TableName = keyof typeof tableSchemas;\n")
+	return s.String()
+}
+
+// interfaceNames names each table's interface, numbering names that would
+// clash with each other or with the types declared next to them
+func interfaceNames(tables map[string]interface{}) map[string]string {
+	taken := map[string]bool{"Json": true, "Tables": true, "TableName": true}
+	names := map[string]string{}
+	for _, table := range sortedKeys(tables) {
+		base := pascalCase(table)
+		name := base
+		for i := 2; taken[name]; i++ {
+			name = fmt.Sprintf("%s%d", base, i)
+		}
+		taken[name] = true
+		names[table] = name
+	}
+	return names
+}
+
+// pascalCase turns a table name like user_profiles into UserProfiles
+func pascalCase(name string) string {
+	var s strings.Builder
+	for _, part := range strings.FieldsFunc(name, func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9')
+	}) {
+		s.WriteString(strings.ToUpper(part[:1]) + part[1:])
+	}
+	if s.Len() == 0 || (s.String()[0] >= '0' && s.String()[0] <= '9') {
+		return "Table" + s.String()
+	}
+	return s.String()
+}
+
+// typeScriptKey quotes property names that aren't plain identifiers
+func typeScriptKey(name string) string {
+	if typeScriptIdentifier.MatchString(name) {
+		return name
+	}
+	quoted, _ := json.Marshal(name)
+	return string(quoted)
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,466 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/charmbracelet/bubbles/table"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/huh"
+	"github.com/charmbracelet/lipgloss"
+)
+
+type schemaVersion struct {
+	Version   int                    `json:"version"`
+	CreatedAt time.Time              `json:"created_at"`
+	Author    string                 `json:"author,omitempty"`
+	Schema    map[string]interface{} `json:"schema,omitempty"`
+}
+
+func getSchemaHistory(projectID string) ([]schemaVersion, error) {
+	url := "https://api.basic.tech/project/" + projectID + "/schema/history"
+	resp, err := apiClient().Get(url)
+	if err != nil {
+		return nil, apiRequestError("error fetching schema history", url, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("received non-200 response fetching schema history: %d", resp.StatusCode)
+	}
+
+	var response struct {
+		Data []schemaVersion `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return nil, fmt.Errorf("error parsing JSON response: %v", err)
+	}
+
+	// newest first
+	sort.Slice(response.Data, func(i, j int) bool {
+		return response.Data[i].Version > response.Data[j].Version
+	})
+
+	return response.Data, nil
+}
+
+// filterSchemaHistory keeps versions published after since (a version number or a date),
+// capped at limit entries when limit > 0
+func filterSchemaHistory(versions []schemaVersion, since string, limit int) ([]schemaVersion, error) {
+	filtered := versions
+
+	if since != "" {
+		filtered = []schemaVersion{}
+		if sinceVersion, err := strconv.Atoi(since); err == nil {
+			for _, v := range versions {
+				if v.Version > sinceVersion {
+					filtered = append(filtered, v)
+				}
+			}
+		} else {
+			sinceTime, err := parseSinceDate(since)
+			if err != nil {
+				return nil, err
+			}
+			for _, v := range versions {
+				if v.CreatedAt.After(sinceTime) {
+					filtered = append(filtered, v)
+				}
+			}
+		}
+	}
+
+	if limit > 0 && len(filtered) > limit {
+		filtered = filtered[:limit]
+	}
+	return filtered, nil
+}
+
+func parseSinceDate(since string) (time.Time, error) {
+	for _, layout := range []string{time.RFC3339, "2006-01-02T15:04", "2006-01-02"} {
+		if t, err := time.ParseInLocation(layout, since, time.Local); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid --since value %q: use a version number or a date like 2024-01-31", since)
+}
+
+// historyLimit returns --limit, or 0 when it isn't set
+func historyLimit() (int, error) {
+	l := flagValue("--limit")
+	if l == "" {
+		return 0, nil
+	}
+	limit, err := strconv.Atoi(l)
+	if err != nil || limit < 0 {
+		return 0, fmt.Errorf("invalid --limit value %q: must be a positive number", l)
+	}
+	return limit, nil
+}
+
+func performHistory() tea.Msg {
+	projectID, err := getLocalProjectID()
+	if err != nil {
+		return errorScreenMsg{errorMessage: fmt.Sprintf("error reading project from config: %v", err)}
+	}
+
+	limit, err := historyLimit()
+	if err != nil {
+		return errorScreenMsg{errorMessage: err.Error(), code: "usage"}
+	}
+
+	versions, err := getSchemaHistory(projectID)
+	if err != nil {
+		return errorScreenMsg{errorMessage: err.Error()}
+	}
+
+	versions, err = filterSchemaHistory(versions, flagValue("--since"), limit)
+	if err != nil {
+		return errorScreenMsg{errorMessage: err.Error(), code: "usage"}
+	}
+
+	if hasFlag("--json") {
+		for i := range versions {
+			versions[i].Schema = nil
+		}
+		out, _ := json.MarshalIndent(versions, "", "  ")
+		fmt.Println(string(out))
+		return tea.Quit()
+	}
+
+	if len(versions) == 0 {
+		fmt.Println("No schema versions found")
+		return tea.Quit()
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "VERSION\tPUBLISHED\tAUTHOR")
+	for _, v := range versions {
+		fmt.Fprintf(w, "%d\t%s\t%s\n", v.Version, v.CreatedAt.Local().Format("2006-01-02 15:04"), v.Author)
+	}
+	w.Flush()
+
+	return tea.Quit()
+}
+
+// schemaHistoryMsg carries the versions 'basic schema history' lists
+type schemaHistoryMsg struct {
+	projectID string
+	versions  []schemaVersion
+	err       error
+}
+
+func loadSchemaHistory() tea.Msg {
+	projectID, err := getLocalProjectID()
+	if err != nil {
+		return schemaHistoryMsg{err: fmt.Errorf("error reading project from config: %v", err)}
+	}
+	limit, err := historyLimit()
+	if err != nil {
+		return schemaHistoryMsg{err: err}
+	}
+	versions, err := getSchemaHistory(projectID)
+	if err != nil {
+		return schemaHistoryMsg{err: err}
+	}
+	// versions stay newest first, which rollbackSchema relies on
+	versions, err = filterSchemaHistory(versions, flagValue("--since"), limit)
+	if err != nil {
+		return schemaHistoryMsg{err: err}
+	}
+	return schemaHistoryMsg{projectID: projectID, versions: versions}
+}
+
+// rollbackSchema writes the schema of version target into the local config,
+// numbered as the version after the newest one so it can be pushed. The config
+// is backed up first. With push, the restored schema is published too.
+func rollbackSchema(projectID string, versions []schemaVersion, target int, push bool) (string, error) {
+	var restored *schemaVersion
+	for i := range versions {
+		if versions[i].Version == target {
+			restored = &versions[i]
+			break
+		}
+	}
+	if restored == nil {
+		return "", fmt.Errorf("version %d not found in the schema history of %s, run 'basic schema history' to list them", target, projectID)
+	}
+	if len(restored.Schema) == 0 {
+		return "", fmt.Errorf("the schema history has no content for version %d", target)
+	}
+
+	// versions are newest first
+	next := versions[0].Version + 1
+	schemaData := map[string]interface{}{}
+	for key, value := range restored.Schema {
+		schemaData[key] = value
+	}
+	schemaData["project_id"] = projectID
+	schemaData["version"] = next
+
+	schema, err := json.MarshalIndent(schemaData, "\t", "\t")
+	if err != nil {
+		return "", fmt.Errorf("error formatting schema JSON: %v", err)
+	}
+
+	configFile, err := findConfigFile()
+	if err != nil {
+		return "", err
+	}
+	backupPath, err := backupConfigFile(configFile, projectID)
+	if err != nil {
+		return "", fmt.Errorf("error backing up config, schema not restored: %v", err)
+	}
+	if err := saveSchemaToConfig(string(schema)); err != nil {
+		return "", fmt.Errorf("error saving schema to config: %v", err)
+	}
+
+	message := fmt.Sprintf("Restored version %d to %s as version %d. Previous config saved to %s", target, configFile, next, backupPath)
+	if !push {
+		return message + "\nRun 'basic push' to publish it.", nil
+	}
+	if _, err := pushProjectSchema(string(schema)); err != nil {
+		return "", fmt.Errorf("%s, but pushing it failed: %v", message, err)
+	}
+	return message + fmt.Sprintf("\nPublished it as version %d.", next), nil
+}
+
+// performSchemaRollback implements 'basic schema rollback <version>'. It
+// overwrites the local schema, so it asks first, or needs --yes when there's
+// no terminal.
+func performSchemaRollback(arg string) tea.Msg {
+	target, err := strconv.Atoi(strings.TrimPrefix(arg, "v"))
+	if err != nil || target < 1 {
+		return usageMsg{text: "Usage: basic schema rollback <version> [--push]"}
+	}
+
+	token, err := loadToken()
+	if err != nil || token == nil {
+		return errorScreenMsg{errorMessage: loggedOutError(err)}
+	}
+	history := loadSchemaHistory().(schemaHistoryMsg)
+	if history.err != nil {
+		return errorScreenMsg{errorMessage: history.err.Error()}
+	}
+
+	push := hasFlag("--push")
+	rollback := func() tea.Msg {
+		message, err := rollbackSchema(history.projectID, history.versions, target, push)
+		if err != nil {
+			return pushSchemaMsg{success: false, message: err.Error()}
+		}
+		return pushSchemaMsg{success: true, message: message}
+	}
+
+	if assumeYes() {
+		return rollback()
+	}
+	if !isInteractive() {
+		return errorScreenMsg{errorMessage: "rollback overwrites the local schema, pass --yes to confirm"}
+	}
+
+	description := "The local schema is replaced, a backup of the config is kept."
+	if push {
+		description = "The local schema is replaced and published, a backup of the config is kept."
+	}
+	return confirmMsg{
+		title:       fmt.Sprintf("Restore schema version %d?", target),
+		description: description,
+		affirmative: "Yes, restore it",
+		run:         rollback,
+		cancelled:   "Rollback cancelled",
+	}
+}
+
+// historyListModel is the interactive 'basic schema history': the published
+// versions in a table, and enter to restore the selected one
+type historyListModel struct {
+	projectID string
+	versions  []schemaVersion
+	table     table.Model
+	height    int
+	// asks how to restore the selected version
+	confirm      *huh.Form
+	restoring    bool
+	notification string
+	err          error
+}
+
+type historyRollbackMsg struct {
+	message string
+	err     error
+}
+
+func openHistoryList(msg schemaHistoryMsg) (tea.Model, tea.Cmd) {
+	t := table.New(
+		table.WithColumns([]table.Column{
+			{Title: "VERSION", Width: 8},
+			{Title: "PUBLISHED", Width: 17},
+			{Title: "AUTHOR", Width: 30},
+			{Title: "TABLES", Width: 7},
+		}),
+		table.WithFocused(true),
+	)
+	t.SetStyles(tableStyles())
+
+	rows := []table.Row{}
+	for _, v := range msg.versions {
+		tables, _ := v.Schema["tables"].(map[string]interface{})
+		rows = append(rows, table.Row{
+			strconv.Itoa(v.Version),
+			v.CreatedAt.Local().Format("2006-01-02 15:04"),
+			v.Author,
+			strconv.Itoa(len(tables)),
+		})
+	}
+	t.SetRows(rows)
+
+	m := historyListModel{projectID: msg.projectID, versions: msg.versions, table: t}
+	m.resize()
+	return m, tea.WindowSize()
+}
+
+func (m *historyListModel) resize() {
+	height := len(m.versions) + 1
+	if m.height > 0 {
+		height = min(height, m.height-8)
+	}
+	m.table.SetHeight(max(height, 3))
+}
+
+func (m historyListModel) selected() (schemaVersion, bool) {
+	cursor := m.table.Cursor()
+	if cursor < 0 || cursor >= len(m.versions) {
+		return schemaVersion{}, false
+	}
+	return m.versions[cursor], true
+}
+
+func (m historyListModel) Init() tea.Cmd {
+	return nil
+}
+
+func (m historyListModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if m.confirm != nil {
+		return m.updateConfirm(msg)
+	}
+
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.height = msg.Height
+		m.resize()
+		return m, nil
+	case historyRollbackMsg:
+		m.restoring = false
+		m.err = msg.err
+		m.notification = msg.message
+		return m, nil
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "ctrl+c", "esc", "q":
+			return m, tea.Quit
+		case "enter", "r":
+			if v, ok := m.selected(); ok && !m.restoring {
+				m.confirm = newRollbackForm(v.Version)
+				return m, m.confirm.Init()
+			}
+			return m, nil
+		}
+	}
+
+	var cmd tea.Cmd
+	m.table, cmd = m.table.Update(msg)
+	return m, cmd
+}
+
+func newRollbackForm(version int) *huh.Form {
+	return huh.NewForm(
+		huh.NewGroup(
+			huh.NewSelect[string]().
+				Key("action").
+				Title(fmt.Sprintf("Restore schema version %d?", version)).
+				Description("The local schema is replaced, a backup of the config is kept.").
+				Options(
+					huh.NewOption("Restore it locally", "restore"),
+					huh.NewOption("Restore and push it", "push"),
+					huh.NewOption("Cancel", "cancel"),
+				),
+		),
+	).WithShowHelp(false)
+}
+
+// updateConfirm runs the restore form, esc goes back to the list
+func (m historyListModel) updateConfirm(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if key, ok := msg.(tea.KeyMsg); ok {
+		switch key.String() {
+		case "ctrl+c":
+			return m, tea.Quit
+		case "esc":
+			m.confirm = nil
+			return m, nil
+		}
+	}
+
+	form, cmd := m.confirm.Update(msg)
+	if f, ok := form.(*huh.Form); ok {
+		m.confirm = f
+		switch f.State {
+		case huh.StateCompleted:
+			m.confirm = nil
+			action := f.GetString("action")
+			v, ok := m.selected()
+			if action == "cancel" || !ok {
+				return m, nil
+			}
+			m.restoring = true
+			m.err = nil
+			m.notification = ""
+			projectID, versions := m.projectID, m.versions
+			return m, func() tea.Msg {
+				message, err := rollbackSchema(projectID, versions, v.Version, action == "push")
+				return historyRollbackMsg{message: message, err: err}
+			}
+		case huh.StateAborted:
+			m.confirm = nil
+			return m, nil
+		}
+	}
+	return m, cmd
+}
+
+func (m historyListModel) View() string {
+	if m.confirm != nil {
+		return "\n" + m.confirm.View() + "\n"
+	}
+
+	var s strings.Builder
+	s.WriteString(fmt.Sprintf("\nSchema history of %s\n\n", m.projectID))
+	if len(m.versions) == 0 {
+		s.WriteString("No schema versions found\n")
+	} else {
+		s.WriteString(m.table.View() + "\n")
+	}
+
+	switch {
+	case m.restoring:
+		s.WriteString("\nRestoring schema...\n")
+	case m.err != nil:
+		s.WriteString("\n" + lipgloss.NewStyle().Foreground(lipgloss.Color("9")).Render(fmt.Sprintf("Error: %v", m.err)) + "\n")
+	case m.notification != "":
+		s.WriteString("\n" + lipgloss.NewStyle().Foreground(lipgloss.Color("57")).Render(m.notification) + "\n")
+	}
+
+	help := "'q' to quit"
+	if len(m.versions) > 0 {
+		help = "enter to restore the selected version • " + help
+	}
+	s.WriteString("\n" + lipgloss.NewStyle().Foreground(lipgloss.Color("240")).Render(help) + "\n")
+	return s.String()
+}
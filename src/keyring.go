@@ -0,0 +1,76 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"runtime"
+
+	"github.com/zalando/go-keyring"
+)
+
+// keyringService is what the token is stored under in the OS keychain, with
+// the profile name as the account
+const keyringService = "basic-cli-oauth"
+
+// noKeyringEnv keeps the token in the plaintext token file, e.g. on CI
+// machines where a keychain prompt would hang
+const noKeyringEnv = "BASIC_NO_KEYRING"
+
+var errNotInKeyring = errors.New("not found in keyring")
+
+// keyringBackend stores secrets in the OS keychain
+type keyringBackend interface {
+	// get returns errNotInKeyring when nothing is stored for account
+	get(account string) (string, error)
+	set(account, secret string) error
+	delete(account string) error
+}
+
+// systemKeyring returns the OS keychain: Keychain on macOS, Secret Service on
+// Linux and Credential Manager on Windows. It's nil when there's none to use,
+// in which case the token stays in the token file.
+func systemKeyring() keyringBackend {
+	if os.Getenv(noKeyringEnv) != "" {
+		return nil
+	}
+	// without a session bus (ssh, containers) there's no Secret Service to reach
+	if runtime.GOOS != "darwin" && runtime.GOOS != "windows" && os.Getenv("DBUS_SESSION_BUS_ADDRESS") == "" {
+		return nil
+	}
+	return osKeyring{}
+}
+
+func keyringAccount() string {
+	return activeProfile()
+}
+
+// osKeyring stores secrets with go-keyring, which talks to each OS keychain
+// directly
+type osKeyring struct{}
+
+func (osKeyring) get(account string) (string, error) {
+	secret, err := keyring.Get(keyringService, account)
+	if errors.Is(err, keyring.ErrNotFound) {
+		return "", errNotInKeyring
+	}
+	if err != nil {
+		return "", fmt.Errorf("error reading from keychain: %v", err)
+	}
+	return secret, nil
+}
+
+func (osKeyring) set(account, secret string) error {
+	if err := keyring.Set(keyringService, account, secret); err != nil {
+		return fmt.Errorf("error writing to keychain: %v", err)
+	}
+	return nil
+}
+
+func (osKeyring) delete(account string) error {
+	err := keyring.Delete(keyringService, account)
+	if err != nil && !errors.Is(err, keyring.ErrNotFound) {
+		return fmt.Errorf("error removing from keychain: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,232 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/atotto/clipboard"
+	tea "github.com/charmbracelet/bubbletea"
+	"golang.org/x/oauth2"
+)
+
+const keysUsage = `Usage:
+  basic keys list
+  basic keys create [name]
+  basic keys revoke <key_id>`
+
+// apiKey is a project API key. The API only returns the full key when it's
+// created, listing keys returns its prefix.
+type apiKey struct {
+	ID         string     `json:"id"`
+	Name       string     `json:"name"`
+	Key        string     `json:"key,omitempty"`
+	Prefix     string     `json:"prefix,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+}
+
+// performKeys implements 'basic keys list/create/revoke' for the project in
+// the local config
+func performKeys() tea.Msg {
+	args := positionalArgs()
+	action := "list"
+	if len(args) > 0 {
+		action = args[0]
+	}
+
+	token, err := loadToken()
+	if err != nil || token == nil {
+		return errorScreenMsg{errorMessage: loggedOutError(err)}
+	}
+	projectID, err := configProjectID()
+	if err != nil {
+		return errorScreenMsg{errorMessage: err.Error()}
+	}
+
+	switch action {
+	case "list":
+		keys, err := getProjectKeys(token, projectID)
+		if err != nil {
+			return errorScreenMsg{errorMessage: err.Error()}
+		}
+		printProjectKeys(keys)
+		return tea.Quit()
+	case "create":
+		name := strings.Join(args[1:], " ")
+		key, err := createProjectKey(token, projectID, name)
+		if err != nil {
+			return errorScreenMsg{errorMessage: err.Error()}
+		}
+		printCreatedKey(key)
+		return tea.Quit()
+	case "revoke":
+		if len(args) < 2 {
+			return usageMsg{text: keysUsage}
+		}
+		keyID := args[1]
+		revoke := func() tea.Msg {
+			if err := revokeProjectKey(token, projectID, keyID); err != nil {
+				return errorScreenMsg{errorMessage: err.Error()}
+			}
+			if hasFlag("--json") {
+				out, _ := json.Marshal(map[string]interface{}{"id": keyID, "revoked": true})
+				fmt.Println(string(out))
+			} else {
+				fmt.Printf("Revoked key %s\n", keyID)
+			}
+			return tea.Quit()
+		}
+		if assumeYes() {
+			return revoke()
+		}
+		if !isInteractive() {
+			return errorScreenMsg{errorMessage: "revoking a key can't be undone, pass --yes to confirm"}
+		}
+		return confirmMsg{
+			title:       fmt.Sprintf("Revoke key %s?", keyID),
+			description: "Apps still using it will stop working right away. This can't be undone.",
+			affirmative: "Yes, revoke it",
+			run:         revoke,
+			cancelled:   "Revoke cancelled",
+		}
+	}
+	return usageMsg{text: keysUsage}
+}
+
+func getProjectKeys(token *oauth2.Token, projectID string) ([]apiKey, error) {
+	url := "https://api.basic.tech/project/" + projectID + "/keys"
+	resp, err := authClient(token).Get(url)
+	if err != nil {
+		return nil, apiRequestError("error fetching API keys", url, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("received non-200 response fetching API keys: %d - %s", resp.StatusCode, string(body))
+	}
+
+	var response struct {
+		Data []apiKey `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return nil, fmt.Errorf("error parsing JSON response: %v", err)
+	}
+	return response.Data, nil
+}
+
+func createProjectKey(token *oauth2.Token, projectID, name string) (apiKey, error) {
+	url := "https://api.basic.tech/project/" + projectID + "/keys"
+	jsonBody, err := json.Marshal(map[string]string{"name": name})
+	if err != nil {
+		return apiKey{}, fmt.Errorf("error encoding request: %v", err)
+	}
+
+	resp, err := authClient(token).Post(url, "application/json", bytes.NewReader(jsonBody))
+	if err != nil {
+		return apiKey{}, apiRequestError("error creating API key", url, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+		body, _ := io.ReadAll(resp.Body)
+		return apiKey{}, fmt.Errorf("received non-200 response creating API key: %d - %s", resp.StatusCode, string(body))
+	}
+
+	var response struct {
+		Data apiKey `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return apiKey{}, fmt.Errorf("error parsing JSON response: %v", err)
+	}
+	if response.Data.Key == "" {
+		return apiKey{}, fmt.Errorf("the API created key %s but didn't return it", response.Data.ID)
+	}
+	return response.Data, nil
+}
+
+func revokeProjectKey(token *oauth2.Token, projectID, keyID string) error {
+	requestURL := "https://api.basic.tech/project/" + projectID + "/keys/" + url.PathEscape(keyID)
+	req, err := http.NewRequest(http.MethodDelete, requestURL, nil)
+	if err != nil {
+		return fmt.Errorf("error creating request: %v", err)
+	}
+
+	resp, err := authClient(token).Do(req)
+	if err != nil {
+		return apiRequestError("error revoking API key", requestURL, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return fmt.Errorf("key %s not found in project %s, run 'basic keys list' to see its keys", keyID, projectID)
+	}
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("received non-200 response revoking API key: %d - %s", resp.StatusCode, string(body))
+	}
+	return nil
+}
+
+func printProjectKeys(keys []apiKey) {
+	if hasFlag("--json") {
+		out, _ := json.MarshalIndent(keys, "", "  ")
+		fmt.Println(string(out))
+		return
+	}
+	if len(keys) == 0 {
+		fmt.Println("This project has no API keys yet. Create one with 'basic keys create'")
+		return
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tNAME\tKEY\tCREATED\tLAST USED")
+	for _, k := range keys {
+		lastUsed := "never"
+		if k.LastUsedAt != nil {
+			lastUsed = k.LastUsedAt.Local().Format("2006-01-02 15:04")
+		}
+		masked := maskKey(k.Key)
+		if k.Key == "" {
+			masked = k.Prefix + "…"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", k.ID, k.Name, masked, k.CreatedAt.Local().Format("2006-01-02 15:04"), lastUsed)
+	}
+	w.Flush()
+}
+
+// printCreatedKey copies the new key to the clipboard rather than printing
+// it, so it doesn't end up in terminal scrollback or CI logs. --json prints it
+// in full for scripts.
+func printCreatedKey(key apiKey) {
+	if hasFlag("--json") {
+		out, _ := json.MarshalIndent(key, "", "  ")
+		fmt.Println(string(out))
+		return
+	}
+
+	fmt.Printf("Created key %s %s\n", key.ID, maskKey(key.Key))
+	if err := clipboard.WriteAll(key.Key); err != nil {
+		// without a clipboard this is the only chance to see the key
+		fmt.Printf("Could not copy it to the clipboard (%v), here it is in full:\n%s\n", err, key.Key)
+	} else {
+		fmt.Println("The key was copied to your clipboard.")
+	}
+	fmt.Println("Store it somewhere safe, it won't be shown again.")
+}
+
+// maskKey keeps enough of key to recognize it
+func maskKey(key string) string {
+	if len(key) <= 12 {
+		return strings.Repeat("•", len(key))
+	}
+	return key[:8] + strings.Repeat("•", 8) + key[len(key)-4:]
+}
@@ -0,0 +1,215 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// projectLink remembers which project a directory was last used with, so we
+// can still point people at the right project if their config goes missing
+type projectLink struct {
+	ProjectID   string    `json:"project_id"`
+	ProjectName string    `json:"project_name,omitempty"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+func (l projectLink) describe() string {
+	if l.ProjectName == "" {
+		return l.ProjectID
+	}
+	return fmt.Sprintf("%s (%s)", l.ProjectName, l.ProjectID)
+}
+
+func getLinksFilePath() (string, error) {
+	basicCliDir, err := getBasicCliDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(basicCliDir, linksFileName), nil
+}
+
+// loadProjectLinks returns the directory -> project mapping, keyed by absolute path
+func loadProjectLinks() (map[string]projectLink, error) {
+	links := map[string]projectLink{}
+
+	linksFilePath, err := getLinksFilePath()
+	if err != nil {
+		return links, err
+	}
+
+	data, err := os.ReadFile(linksFilePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return links, nil
+		}
+		return links, err
+	}
+
+	if err := json.Unmarshal(data, &links); err != nil {
+		return map[string]projectLink{}, fmt.Errorf("error parsing %s: %v", linksFilePath, err)
+	}
+	return links, nil
+}
+
+// saveProjectLink links the current directory to projectID. Failing to save
+// the link is never fatal, so errors are only returned for callers that care.
+func saveProjectLink(projectID string, projectName string) error {
+	dir, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+
+	links, err := loadProjectLinks()
+	if err != nil {
+		return err
+	}
+
+	// keep the name we already know if the caller doesn't have one
+	if existing, ok := links[dir]; ok && projectName == "" && existing.ProjectID == projectID {
+		projectName = existing.ProjectName
+	}
+	links[dir] = projectLink{ProjectID: projectID, ProjectName: projectName, UpdatedAt: time.Now()}
+
+	data, err := json.MarshalIndent(links, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	linksFilePath, err := getLinksFilePath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(linksFilePath), 0700); err != nil {
+		return err
+	}
+	return os.WriteFile(linksFilePath, data, 0600)
+}
+
+// getProjectLink returns the project the current directory was last linked to
+func getProjectLink() (projectLink, bool) {
+	dir, err := os.Getwd()
+	if err != nil {
+		return projectLink{}, false
+	}
+
+	links, err := loadProjectLinks()
+	if err != nil {
+		return projectLink{}, false
+	}
+
+	link, ok := links[dir]
+	return link, ok
+}
+
+// localSyncStatuses compares the schema version of every local config we know
+// about (the current directory and linked directories) with its remote
+// version, returning project ID -> "synced", "behind" or "ahead"
+func localSyncStatuses() map[string]string {
+	localVersions := map[string]float64{}
+
+	dirs := []string{"."}
+	if links, err := loadProjectLinks(); err == nil {
+		for dir := range links {
+			dirs = append(dirs, dir)
+		}
+	}
+
+	for _, dir := range dirs {
+		for _, name := range configFileNames {
+			schema, err := readSchemaFromFile(filepath.Join(dir, name))
+			if err != nil {
+				continue
+			}
+			schemaData, err := parseSchemaJSON(schema)
+			if err != nil {
+				continue
+			}
+			projectID, _ := schemaData["project_id"].(string)
+			version, err := readSchemaVersion(schemaData)
+			if _, seen := localVersions[projectID]; projectID != "" && err == nil && !seen {
+				localVersions[projectID] = version
+			}
+			break
+		}
+	}
+
+	remoteVersions := fetchRemoteVersions(localVersions)
+	statuses := map[string]string{}
+	for projectID, localVersion := range localVersions {
+		remoteVersion, ok := remoteVersions[projectID]
+		if !ok {
+			continue
+		}
+		switch {
+		case localVersion < remoteVersion:
+			statuses[projectID] = "behind"
+		case localVersion > remoteVersion:
+			statuses[projectID] = "ahead"
+		default:
+			statuses[projectID] = "synced"
+		}
+	}
+	return statuses
+}
+
+// syncStatusBudget is how long listing projects waits for remote versions, so
+// a slow or offline API doesn't hold up the table
+const syncStatusBudget = 3 * time.Second
+
+// fetchRemoteVersions fetches the remote schema version of every project at
+// once. Projects that don't answer within syncStatusBudget fall back to their
+// cached remote schema, if there is one.
+func fetchRemoteVersions(projects map[string]float64) map[string]float64 {
+	type result struct {
+		projectID string
+		schema    string
+		err       error
+	}
+	results := make(chan result, len(projects))
+	for projectID := range projects {
+		go func(projectID string) {
+			schema, err := getProjectSchema(projectID)
+			results <- result{projectID: projectID, schema: schema, err: err}
+		}(projectID)
+	}
+
+	fetched := map[string]string{}
+	deadline := time.After(syncStatusBudget)
+collect:
+	for range projects {
+		select {
+		case r := <-results:
+			if r.err == nil {
+				fetched[r.projectID] = r.schema
+			}
+		case <-deadline:
+			break collect
+		}
+	}
+
+	cache := loadRemoteSchemaCache()
+	versions := map[string]float64{}
+	for projectID := range projects {
+		remote, ok := fetched[projectID]
+		if ok {
+			cacheRemoteSchema(projectID, remote)
+		} else if entry, cached := cache[projectID]; cached {
+			remote = entry.Schema
+		} else {
+			continue
+		}
+
+		// an unpublished or versionless remote schema counts as version 0
+		var version float64
+		if remote != "" {
+			if remoteSchema, err := parseSchemaJSON(remote); err == nil {
+				version, _ = readSchemaVersion(remoteSchema)
+			}
+		}
+		versions[projectID] = version
+	}
+	return versions
+}
@@ -5,16 +5,25 @@ import (
 	"context"
 	"crypto/rand"
 	"encoding/base64"
+	"encoding/csv"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"net"
 	"net/http"
+	"net/url"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"regexp"
 	"runtime"
+	"sort"
+	"strconv"
 	"strings"
+	"sync"
+	"syscall"
+	"text/tabwriter"
 	"time"
 
 	"github.com/charmbracelet/bubbles/spinner"
@@ -25,6 +34,8 @@ import (
 	"github.com/atotto/clipboard"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/mattn/go-isatty"
+	"github.com/muesli/termenv"
 	"golang.org/x/oauth2"
 )
 
@@ -41,9 +52,17 @@ var (
 )
 
 const (
-	basicCliDirName = ".basic-cli"
-	tokenFileName   = "token.json"
-	version         = "0.0.12"
+	basicCliDirName           = ".basic-cli"
+	tokenFileName             = "token.json"
+	linksFileName             = "projects.json"
+	settingsFileName          = "settings.json"
+	profilesDirName           = "profiles"
+	projectsCacheFileName     = "projects-cache.json"
+	remoteSchemaCacheFileName = "remote-schemas.json"
+	validationCacheFileName   = "validation-cache.json"
+	backupsDirName            = "backups"
+	accountFileName           = "account.json"
+	version                   = "0.0.12"
 )
 
 type Styles struct {
@@ -74,30 +93,30 @@ func createConfigFile(name string, projectID string, option string, schema strin
 	}
 	`, projectID)
 
-	// fmt.Println("schema", schema)
 	if schema == "" {
 		schema = defaultSchema
 	}
 
-	content := fmt.Sprintf(`
-// Basic Project Configuration
-// see  the docs for more info: https://docs.basic.tech
-export const config = {
-  name: "%s",
-  project_id: "%s"
-};
+	format := configFormatNamed(option)
+	filename := format.filename
 
-export const schema = %s;
-`, name, projectID, schema)
-
-	var filename string
-	if option == "typescript" {
-		filename = "basic.config.ts"
-	} else {
-		filename = "basic.config.js"
+	// --config-out overrides the destination; the format follows its extension
+	if configOut := flagValue("--config-out"); configOut != "" {
+		if err := checkConfigOutPath(configOut); err != nil {
+			return err
+		}
+		filename = configOut
+		format, _ = configFormatOf(configOut)
+		if err := os.MkdirAll(filepath.Dir(filename), 0755); err != nil {
+			return fmt.Errorf("failed to create config directory: %v", err)
+		}
 	}
 
-	err := os.WriteFile(filename, []byte(content), 0644)
+	content, err := format.create(name, projectID, schema)
+	if err != nil {
+		return fmt.Errorf("failed to create config file: %v", err)
+	}
+	err = os.WriteFile(filename, []byte(content), 0644)
 	if err != nil {
 		return fmt.Errorf("failed to create config file: %v", err)
 	}
@@ -106,6 +125,13 @@ export const schema = %s;
 	return nil
 }
 
+func checkConfigOutPath(path string) error {
+	if _, ok := configFormatOf(path); !ok {
+		return fmt.Errorf("--config-out must be a .ts, .js, .json or .yaml file, got %s", path)
+	}
+	return nil
+}
+
 func NewStyles(lg *lipgloss.Renderer) *Styles {
 	s := Styles{}
 	s.Base = lg.NewStyle().
@@ -156,6 +182,16 @@ type FormModel struct {
 	projectCreated bool
 	fileCreated    bool
 	projects       []project
+	exitCode       int
+
+	// asks how to recover when init fails halfway: "config" when a created
+	// project's config couldn't be written, "schema" when an existing
+	// project's schema couldn't be fetched
+	retryForm *huh.Form
+	retryKind string
+
+	// set when the user chose to continue without the remote schema
+	useDefaultSchema bool
 }
 
 func min(x, y int) int {
@@ -175,6 +211,37 @@ type errorMsg struct {
 
 type errorScreenMsg struct {
 	errorMessage string
+	// category for --json output; derived from the message when empty
+	code string
+}
+
+// jsonError is what every command prints to stderr on failure when --json is set
+type jsonError struct {
+	Error string `json:"error"`
+	Code  string `json:"code"`
+}
+
+func errorCode(message string) string {
+	switch {
+	case message == offlineMessage:
+		return "offline"
+	case message == loggedOutMessage:
+		return "not_logged_in"
+	case strings.Contains(message, "already exists"):
+		return "already_exists"
+	case strings.Contains(message, "not found") || strings.Contains(message, "no basic.config"):
+		return "not_found"
+	}
+	return "error"
+}
+
+// printJSONError writes the --json error envelope to stderr
+func printJSONError(message, code string) {
+	if code == "" {
+		code = errorCode(message)
+	}
+	out, _ := json.Marshal(jsonError{Error: message, Code: code})
+	fmt.Fprintln(os.Stderr, string(out))
 }
 
 type formSuccessMsg struct {
@@ -182,6 +249,79 @@ type formSuccessMsg struct {
 	projectID   string
 }
 
+// detectConfigLanguage picks the config language for init: "javascript" for
+// JS projects without TypeScript, "typescript" otherwise
+func detectConfigLanguage() string {
+	if _, err := os.Stat("tsconfig.json"); err == nil {
+		return "typescript"
+	}
+
+	data, err := os.ReadFile("package.json")
+	if err != nil {
+		return "typescript"
+	}
+
+	var pkg struct {
+		Dependencies    map[string]string `json:"dependencies"`
+		DevDependencies map[string]string `json:"devDependencies"`
+	}
+	if json.Unmarshal(data, &pkg) != nil {
+		return "typescript"
+	}
+	if _, ok := pkg.Dependencies["typescript"]; ok {
+		return "typescript"
+	}
+	if _, ok := pkg.DevDependencies["typescript"]; ok {
+		return "typescript"
+	}
+	return "javascript"
+}
+
+// sdkPackage is the npm package suggested after init
+const sdkPackage = "@basictech/react"
+
+// sdkInstallCommand returns the command that adds the Basic SDK using the
+// package manager of the project in the current directory, or "" if there's
+// no package.json
+func sdkInstallCommand() string {
+	data, err := os.ReadFile("package.json")
+	if err != nil {
+		return ""
+	}
+
+	installCommands := map[string]string{
+		"npm":  "npm install " + sdkPackage,
+		"yarn": "yarn add " + sdkPackage,
+		"pnpm": "pnpm add " + sdkPackage,
+		"bun":  "bun add " + sdkPackage,
+	}
+
+	// an explicit "packageManager": "pnpm@9.1.0" wins over lockfiles
+	var pkg struct {
+		PackageManager string `json:"packageManager"`
+	}
+	if json.Unmarshal(data, &pkg) == nil && pkg.PackageManager != "" {
+		name, _, _ := strings.Cut(pkg.PackageManager, "@")
+		if command, ok := installCommands[name]; ok {
+			return command
+		}
+	}
+
+	lockfiles := []struct{ file, manager string }{
+		{"bun.lockb", "bun"},
+		{"bun.lock", "bun"},
+		{"pnpm-lock.yaml", "pnpm"},
+		{"yarn.lock", "yarn"},
+		{"package-lock.json", "npm"},
+	}
+	for _, l := range lockfiles {
+		if _, err := os.Stat(l.file); err == nil {
+			return installCommands[l.manager]
+		}
+	}
+	return installCommands["npm"]
+}
+
 func NewFormModel() FormModel {
 	m := FormModel{width: maxWidth}
 	m.screen = "form"
@@ -207,24 +347,10 @@ func NewFormModel() FormModel {
 		return FormModel{}
 	}
 	m.projects = projects
-	// type projectOption struct {
-	// 	Name string
-	// 	ID   string
-	// }
-	// options := []projectOption{}
-	// for _, project := range projects {
-	// 	options = append(options, projectOption{
-	// 		Name: project.Name,
-	// 		ID:   project.ID,
-	// 	})
-	// }
-
-	options := []huh.Option[string]{}
-	for _, project := range projects {
-		options = append(options, huh.NewOption(project.Name, project.ID))
-	}
 
-	// var selection string
+	// pre-select the language the directory already uses
+	newConfigOption := detectConfigLanguage()
+	existingConfigOption := newConfigOption
 
 	m.form = huh.NewForm(
 		huh.NewGroup(
@@ -251,7 +377,8 @@ func NewFormModel() FormModel {
 			huh.NewSelect[string]().
 				Key("option").
 				Title("Generate config file?").
-				Options(huh.NewOptions("typescript", "javascript", "none")...),
+				Options(huh.NewOptions(append(configFormatNames(), "none")...)...).
+				Value(&newConfigOption),
 
 			huh.NewConfirm().
 				Key("done").
@@ -263,23 +390,13 @@ func NewFormModel() FormModel {
 		}),
 
 		huh.NewGroup(
-			huh.NewSelect[string]().
-				Key("id").
-				Title("Select Project").
-				// Options(huh.NewOptions(options...)...).
-				Options(options...).
-				Height(10).
-				Validate(func(v string) error {
-					if v == "" {
-						return fmt.Errorf("project is required")
-					}
-					return nil
-				}),
+			newProjectPicker("id", "Select Project", projects),
 
 			huh.NewSelect[string]().
 				Key("option").
 				Title("Generate config file?").
-				Options(huh.NewOptions("typescript", "javascript", "none")...),
+				Options(huh.NewOptions(append(configFormatNames(), "none")...)...).
+				Value(&existingConfigOption),
 
 			huh.NewConfirm().
 				Key("done").
@@ -308,6 +425,10 @@ func (m FormModel) Init() tea.Cmd {
 }
 
 func (m FormModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if m.retryForm != nil {
+		return m.updateRetryForm(msg)
+	}
+
 	switch msg := msg.(type) {
 	case tea.WindowSizeMsg:
 		m.width = min(msg.Width, maxWidth) - m.styles.Base.GetHorizontalFrameSize()
@@ -343,21 +464,41 @@ func (m FormModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.projectCreated = true
 		m.projectID = msg.projectID
 
-		schema := ""
-		if m.formStage == "existing" {
-			gotSchema, err := getProjectSchema(msg.projectID)
-			if err != nil {
-				return m, func() tea.Msg {
-					return errorMsg{err: err}
+		// new projects may be created with a starter schema server-side, so
+		// fetch it for both flows and only fall back to the default when empty
+		var schema string
+		var err error
+		if !m.useDefaultSchema {
+			schema, err = getProjectSchema(msg.projectID)
+		}
+		if err != nil {
+			if m.formStage == "existing" {
+				if isInteractive() {
+					return m.askSchemaFetchRecovery(err)
+				}
+				if !assumeYes() {
+					return m, func() tea.Msg {
+						return errorMsg{err: fmt.Errorf("%v\npass --yes to create the config with the default schema anyway", err)}
+					}
 				}
+				fmt.Printf("Could not fetch the project's schema (%v), using the default schema\n", err)
+				m.useDefaultSchema = true
 			}
-
-			schema = gotSchema
-
+			schema = ""
 		}
 
-		err := createConfigFile(msg.projectName, msg.projectID, m.configOption, schema)
+		// link the directory first, so a project we just created isn't lost
+		// if the config can't be written
+		saveProjectLink(msg.projectID, msg.projectName)
+
+		err = createConfigFile(msg.projectName, msg.projectID, m.configOption, schema)
 		if err != nil {
+			if m.formStage == "new" && isInteractive() {
+				return m.askRetryConfig(err)
+			}
+			if m.formStage == "new" {
+				err = fmt.Errorf("%v\nproject %s was created, run 'basic init' and choose 'Use existing project' to write its config", err, msg.projectID)
+			}
 			return m, func() tea.Msg {
 				return errorMsg{err: err}
 			}
@@ -371,10 +512,24 @@ func (m FormModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 
 	case errorMsg:
+		m.exitCode = exitError
+		if hasFlag("--json") {
+			printJSONError(msg.err.Error(), "")
+			return m, tea.Quit
+		}
 		m.screen = "error"
 		m.errorMessage = msg.err.Error()
 		return m, nil
 	case errorScreenMsg:
+		code := msg.code
+		if code == "" {
+			code = errorCode(msg.errorMessage)
+		}
+		m.exitCode = exitCodeFor(code)
+		if hasFlag("--json") {
+			printJSONError(msg.errorMessage, code)
+			return m, tea.Quit
+		}
 		m.screen = "error_screen"
 		m.errorMessage = msg.errorMessage
 		return m, tea.Quit
@@ -430,9 +585,98 @@ func (m FormModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, tea.Batch(cmds...)
 }
 
+// askRetryConfig offers to write the config again for a project that was
+// created but whose config couldn't be written, instead of creating a
+// duplicate project on the next 'basic init'
+func (m FormModel) askRetryConfig(err error) (tea.Model, tea.Cmd) {
+	m.retryKind = "config"
+	m.retryForm = huh.NewForm(
+		huh.NewGroup(
+			huh.NewConfirm().
+				Key("retry").
+				Title(fmt.Sprintf("Project %s (%s) was created, but its config couldn't be written", m.projectName, m.projectID)).
+				Description(err.Error()).
+				Affirmative("Retry writing config").
+				Negative("Give up"),
+		),
+	).WithShowHelp(false)
+	return m, m.retryForm.Init()
+}
+
+// askSchemaFetchRecovery keeps the user's init choices when the existing
+// project's schema can't be fetched, offering to retry or to continue with
+// the default schema
+func (m FormModel) askSchemaFetchRecovery(err error) (tea.Model, tea.Cmd) {
+	m.retryKind = "schema"
+	m.retryForm = huh.NewForm(
+		huh.NewGroup(
+			huh.NewSelect[string]().
+				Key("recovery").
+				Title(fmt.Sprintf("Couldn't fetch the schema of %s", m.projectID)).
+				Description(err.Error()).
+				Options(
+					huh.NewOption("Try again", "retry"),
+					huh.NewOption("Create the config with the default schema", "default"),
+					huh.NewOption("Cancel", "cancel"),
+				),
+		),
+	).WithShowHelp(false)
+	return m, m.retryForm.Init()
+}
+
+func (m FormModel) updateRetryForm(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if msg, ok := msg.(tea.KeyMsg); ok && msg.String() == "ctrl+c" {
+		return m, tea.Quit
+	}
+
+	form, cmd := m.retryForm.Update(msg)
+	f, ok := form.(*huh.Form)
+	if !ok {
+		return m, cmd
+	}
+	m.retryForm = f
+	if f.State != huh.StateCompleted && f.State != huh.StateAborted {
+		return m, cmd
+	}
+
+	m.retryForm = nil
+	if m.retryKind == "schema" {
+		recovery := ""
+		if f.State == huh.StateCompleted {
+			recovery = f.GetString("recovery")
+		}
+		switch recovery {
+		case "retry", "default":
+			m.useDefaultSchema = recovery == "default"
+			return m, func() tea.Msg {
+				return newProjectMsg{projectName: m.projectName, projectID: m.projectID}
+			}
+		default:
+			return m, func() tea.Msg {
+				return errorMsg{err: fmt.Errorf("init cancelled, the schema of %s couldn't be fetched", m.projectID)}
+			}
+		}
+	}
+
+	if f.State == huh.StateCompleted && f.GetBool("retry") {
+		// the project exists now, so only the config is written again
+		return m, func() tea.Msg {
+			return newProjectMsg{projectName: m.projectName, projectID: m.projectID}
+		}
+	}
+	return m, func() tea.Msg {
+		return errorMsg{err: fmt.Errorf("project %s was created, but has no config yet. "+
+			"Run 'basic init' and choose 'Use existing project' to write it", m.projectID)}
+	}
+}
+
 func (m FormModel) View() string {
 	s := m.styles
 
+	if m.retryForm != nil {
+		return m.retryForm.View()
+	}
+
 	switch m.screen {
 	case "loading":
 		status := "Creating your project...\n"
@@ -451,6 +695,14 @@ func (m FormModel) View() string {
 
 		fmt.Fprintf(&b, "Project ID: %s\n\n", m.projectID)
 
+		if m.useDefaultSchema {
+			fmt.Fprintf(&b, "The config uses the default schema. Run 'basic pull' once you're back online to get the project's schema.\n\n")
+		}
+
+		if install := sdkInstallCommand(); install != "" {
+			fmt.Fprintf(&b, "Add the Basic SDK to your app:\n\n  %s\n", install)
+		}
+
 		fmt.Fprintf(&b, "\n\n\nCheckout https://docs.basic.tech if you need help getting started.")
 		return s.Status.Margin(0, 1).Padding(1, 2).Width(48).Render(b.String()) + "\n\n"
 	case "error":
@@ -589,7 +841,7 @@ func createNewProjectMsg(projectName string, projectSlug string) tea.Msg {
 		return newProjectMsg{err: fmt.Errorf("token has expired. please login again with 'basic login'")}
 	}
 
-	client := oauthConfig.Client(context.Background(), token)
+	client := authClient(token)
 
 	payload := map[string]string{
 		"name": projectName,
@@ -600,9 +852,10 @@ func createNewProjectMsg(projectName string, projectSlug string) tea.Msg {
 		return newProjectMsg{err: fmt.Errorf("error creating JSON payload: %v", err)}
 	}
 
-	resp, err := client.Post("https://api.basic.tech/project/new", "application/json", bytes.NewBuffer(jsonPayload))
+	url := "https://api.basic.tech/project/new"
+	resp, err := client.Post(url, "application/json", bytes.NewBuffer(jsonPayload))
 	if err != nil {
-		return newProjectMsg{err: fmt.Errorf("error creating new project: %v", err)}
+		return newProjectMsg{err: apiRequestError("error creating new project", url, err)}
 	}
 	defer resp.Body.Close()
 
@@ -641,9 +894,19 @@ type model struct {
 	suggestions  []string
 
 	currentProjectID string
+	// set while the user is answering a confirmMsg
+	pendingConfirm *confirmMsg
+	// set while the user is picking a profile in 'account switch'
+	switchingProfile bool
+	// set while the user is answering a projectPickMsg
+	pendingPick *projectPickMsg
 
 	messages     []string
 	showMessages bool
+	watchHeader  string
+
+	// exit code for the process once the program finishes
+	exitCode int
 
 	// Add new status-related fields
 	statusMessages []string
@@ -659,19 +922,16 @@ const (
 	stateError
 	stateUnknown
 	stateStatus // Add this new state
+	stateWatching
 )
 
 var (
 	oauthConfig *oauth2.Config
 	oauthState  string
-	authDone    chan bool
+	// receives nil once the callback saved a token, or the reason login failed
+	authDone chan error
 )
 
-// const (
-// 	keyringService = "basic-cli-oauth"
-// 	tokenKey       = "basic-cli-oauth-token"
-// )
-
 func init() {
 	// TODO: add scopes
 	oauthConfig = &oauth2.Config{
@@ -690,22 +950,113 @@ func init() {
 	}
 
 	oauthState = base64.RawURLEncoding.EncodeToString(randomBytes)
-	authDone = make(chan bool)
+	authDone = make(chan error)
 }
 
 func main() {
 	if len(os.Args) < 2 {
-		fmt.Println("welcome to basic-cli! use 'basic help' to see all commands")
-		os.Exit(0)
+		if !isatty.IsTerminal(os.Stdout.Fd()) {
+			fmt.Println("welcome to basic-cli! use 'basic help' to see all commands")
+			os.Exit(0)
+		}
+
+		command, err := chooseCommand()
+		if err != nil {
+			os.Exit(0)
+		}
+		os.Args = append(os.Args, strings.Fields(command)...)
 	}
 
 	command := os.Args[1]
+	if target, ok := commandAliases[command]; ok {
+		command = target
+	}
+	cliArgs = os.Args[2:]
+
+	if hasFlag("--help", "-h") {
+		os.Exit(printCommandHelp(command))
+	}
+	if command == "help" && len(positionalArgs()) > 0 {
+		os.Exit(printCommandHelp(positionalArgs()[0]))
+	}
+	err := checkFlags(command)
+	if err == nil {
+		err = checkEnvironmentFlag()
+	}
+	if err != nil {
+		if hasFlag("--json") {
+			printJSONError(err.Error(), "usage")
+		} else {
+			fmt.Fprintln(os.Stderr, err)
+		}
+		os.Exit(exitUsage)
+	}
+
+	// prompts call this constantly, so skip the UI entirely
+	if command == "status" && hasFlag("--prompt") {
+		fmt.Print(statusPrompt())
+		return
+	}
+
+	if command == "auth" {
+		os.Exit(printAuthToken())
+	}
 
-	p := tea.NewProgram(initialModel(command))
-	if _, err := p.Run(); err != nil {
+	// lipgloss strips colors when stdout isn't a terminal; CLICOLOR_FORCE is
+	// handled by lipgloss itself
+	if hasFlag("--force-color") {
+		lipgloss.SetColorProfile(termenv.ANSI256)
+	}
+
+	var opts []tea.ProgramOption
+	headless := runsHeadless(command)
+	if headless {
+		// keep stdout clean for scripts, and don't read keys from stdin: it
+		// holds the schema, or there may be no terminal at all in CI
+		opts = append(opts, tea.WithoutRenderer(), tea.WithInput(nil))
+	}
+
+	p := tea.NewProgram(initialModel(command), opts...)
+	finalModel, err := p.Run()
+	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error running app: %v\n", err)
 		os.Exit(1)
 	}
+	// without a renderer nothing was drawn, so print where the command ended
+	// up. --json and quiet runs print their own output.
+	if headless && !hasFlag("--json") && !quietValidate(command) {
+		if view := strings.TrimRight(finalModel.View(), "\n"); view != "" {
+			fmt.Println(view)
+		}
+	}
+
+	switch m := finalModel.(type) {
+	case model:
+		os.Exit(m.exitCode)
+	case FormModel:
+		os.Exit(m.exitCode)
+	}
+}
+
+// chooseCommand shows a menu of commands for people who run 'basic' on its own
+func chooseCommand() (string, error) {
+	options := []huh.Option[string]{}
+	for _, c := range commandList {
+		// commands that need arguments can't be run from the menu
+		if strings.Contains(c.name, "<") {
+			continue
+		}
+		options = append(options, huh.NewOption(fmt.Sprintf("%-14s %s", c.name, c.description), c.name))
+	}
+
+	var command string
+	err := huh.NewSelect[string]().
+		Title("welcome to basic-cli! what would you like to do?").
+		Options(options...).
+		Height(12).
+		Value(&command).
+		Run()
+	return command, err
 }
 
 func initialModel(command string) model {
@@ -729,9 +1080,54 @@ func (m model) Init() tea.Cmd {
 			checkStatusCmd,
 		)
 	}
+	// commands start on the first message, usually the terminal size. Running
+	// headless with output piped there's neither.
+	if runsHeadless(m.choice) && !isatty.IsTerminal(os.Stdout.Fd()) {
+		return func() tea.Msg { return startMsg{} }
+	}
 	return nil
 }
 
+// startMsg kicks off the command when no other message will
+type startMsg struct{}
+
+// exit codes, so scripts can tell what went wrong
+const (
+	exitOK          = 0
+	exitError       = 1
+	exitUsage       = 2
+	exitNotLoggedIn = 3
+	exitOffline     = 4
+	// the schema is invalid or conflicts with the remote schema
+	exitSchema = 5
+)
+
+// exitCodeFor maps an error category from errorCode to an exit code
+func exitCodeFor(code string) int {
+	switch code {
+	case "offline":
+		return exitOffline
+	case "not_logged_in":
+		return exitNotLoggedIn
+	case "usage":
+		return exitUsage
+	case "schema":
+		return exitSchema
+	}
+	return exitError
+}
+
+// usageMsg prints how to use a command and exits with exitUsage
+type usageMsg struct {
+	text string
+}
+
+func usage(text string) tea.Cmd {
+	return func() tea.Msg {
+		return usageMsg{text: text}
+	}
+}
+
 const (
 	offlineMessage   = "you are offline. please check your internet connection."
 	loggedOutMessage = "you are not logged in. please login with 'basic login'"
@@ -753,6 +1149,36 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			if m.form.State == huh.StateCompleted {
 				confirmed := m.form.GetBool("confirm")
 				m.form = nil
+				if m.switchingProfile {
+					m.switchingProfile = false
+					profile := f.GetString("profile")
+					return m, func() tea.Msg {
+						return performProfileSwitch(profile)
+					}
+				}
+				if m.pendingPick != nil {
+					pick := m.pendingPick
+					m.pendingPick = nil
+					projectID := f.GetString("project")
+					return m, func() tea.Msg {
+						return pick.run(projectID)
+					}
+				}
+				if m.pendingConfirm != nil {
+					pending := m.pendingConfirm
+					m.pendingConfirm = nil
+					if pending.typeToConfirm != "" {
+						confirmed = f.GetString("typed") == pending.typeToConfirm
+					}
+					if !confirmed && pending.declined != nil {
+						return m, pending.declined
+					}
+					if !confirmed {
+						m.messages = append(m.messages, pending.cancelled)
+						return m, tea.Quit
+					}
+					return m, pending.run
+				}
 				if confirmed {
 					m.messages = append(m.messages, "Pulling schema...")
 					return m, func() tea.Msg {
@@ -782,15 +1208,77 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 		case projectsMsg:
 			if msg.err != nil {
-				fmt.Println("Error:", msg.err)
+				m.exitCode = exitError
+				if hasFlag("--json") {
+					printJSONError(msg.err.Error(), "")
+					return m, tea.Quit
+				}
+				m.state = stateError
+				m.errorMessage = msg.err.Error()
 				return m, tea.Quit
 			}
-			return displayProjects(msg.projects)
+			if err := sortProjects(msg.projects); err != nil {
+				return m, func() tea.Msg {
+					return errorScreenMsg{errorMessage: err.Error()}
+				}
+			}
+			if !msg.cachedAt.IsZero() && hasFlag("--json", "--csv", "--fields") {
+				// keep stdout parseable, the banner goes to stderr
+				fmt.Fprintln(os.Stderr, cachedProjectsBanner(msg.cachedAt))
+			}
+			if hasFlag("--json", "--csv", "--fields") {
+				return m, func() tea.Msg {
+					return printProjects(msg.projects)
+				}
+			}
+			return displayProjects(msg.projects, msg.syncStatuses, msg.cachedAt, msg.userID)
+		case dataSchemaMsg:
+			if msg.err != nil {
+				return m, func() tea.Msg {
+					return errorScreenMsg{errorMessage: msg.err.Error()}
+				}
+			}
+			return openDataBrowser(msg)
+		case schemaHistoryMsg:
+			if msg.err != nil {
+				return m, func() tea.Msg {
+					return errorScreenMsg{errorMessage: msg.err.Error()}
+				}
+			}
+			return openHistoryList(msg)
+		case teamMsg:
+			return openTeamTable(msg)
+		case recordEditMsg:
+			return m, editRecord(msg)
+		case recordEditedMsg:
+			return m, func() tea.Msg {
+				return msg.save(msg.payload)
+			}
+		case usageMsg:
+			fmt.Println(msg.text)
+			m.exitCode = exitUsage
+			return m, tea.Quit
 		case errorScreenMsg:
+			code := msg.code
+			if code == "" {
+				code = errorCode(msg.errorMessage)
+			}
+			m.exitCode = exitCodeFor(code)
+			if hasFlag("--json") {
+				printJSONError(msg.errorMessage, code)
+				return m, tea.Quit
+			}
 			m.state = stateError
 			m.errorMessage = msg.errorMessage
 			return m, tea.Quit
 		case pushSchemaMsg:
+			if !msg.success {
+				m.exitCode = max(msg.exitCode, exitError)
+			}
+			if !msg.success && hasFlag("--json") {
+				printJSONError(msg.message, "push_failed")
+				return m, tea.Quit
+			}
 			m.showMessages = true
 			if msg.success {
 				m.messages = append(m.messages, msg.message)
@@ -798,8 +1286,47 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.messages = append(m.messages, msg.message)
 			}
 			return m, tea.Quit
+		case confirmMsg:
+			m.pendingConfirm = &msg
+			negative := "No, cancel"
+			if msg.declined != nil {
+				negative = "No, skip it"
+			}
+			var field huh.Field = huh.NewConfirm().
+				Key("confirm").
+				Title(msg.title).
+				Description(msg.description).
+				Affirmative(msg.affirmative).
+				Negative(negative)
+			if msg.typeToConfirm != "" {
+				field = huh.NewInput().
+					Key("typed").
+					Title(msg.title).
+					Description(fmt.Sprintf("%s\nType %q to confirm, or press esc to cancel.", msg.description, msg.typeToConfirm)).
+					Validate(func(v string) error {
+						if v != msg.typeToConfirm {
+							return fmt.Errorf("type %q to confirm", msg.typeToConfirm)
+						}
+						return nil
+					})
+			}
+			form := huh.NewForm(huh.NewGroup(field)).WithShowHelp(false)
+
+			m.form = form
+			m.form.Init()
+			return m, nil
+		case projectPickMsg:
+			m.pendingPick = &msg
+			m.form = huh.NewForm(huh.NewGroup(newProjectPicker("project", msg.title, msg.projects))).WithShowHelp(false)
+			return m, m.form.Init()
 		case pullSchemaConfirmMsg:
 			m.currentProjectID = msg.projectID
+			if assumeYes() {
+				m.messages = append(m.messages, msg.message, "Pulling schema (--yes)...")
+				return m, func() tea.Msg {
+					return pullSchemaConfirmCmd(msg.projectID)
+				}
+			}
 			form := huh.NewForm(
 				huh.NewGroup(
 					huh.NewConfirm().
@@ -814,7 +1341,42 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.form = form
 			m.form.Init()
 			return m, nil
+		case doctorMsg:
+			m.showMessages = true
+			for _, r := range msg.results {
+				m.messages = append(m.messages, formatDoctorResult(r))
+				if r.status == "fail" {
+					m.exitCode = exitError
+				}
+			}
+			return m, tea.Quit
+		case validateResultMsg:
+			m.exitCode = msg.exitCode
+			if quietValidate(m.choice) {
+				// there's no renderer with --quiet, so print the errors ourselves
+				for _, line := range msg.lines {
+					fmt.Println(line)
+				}
+				return m, tea.Quit
+			}
+			m.showMessages = true
+			m.messages = append(m.messages, msg.lines...)
+			return m, tea.Quit
+		case pullCheckMsg:
+			m.showMessages = true
+			m.messages = append(m.messages, msg.lines...)
+			if msg.drifted {
+				m.exitCode = exitError
+			}
+			return m, tea.Quit
 		case pullSchemaMsg:
+			if !msg.success {
+				m.exitCode = exitError
+			}
+			if !msg.success && hasFlag("--json") {
+				printJSONError(msg.message, "pull_failed")
+				return m, tea.Quit
+			}
 			m.showMessages = true
 			if msg.success {
 				m.messages = append(m.messages, msg.message)
@@ -829,14 +1391,40 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			fmt.Printf("hi bestie :)\n")
 			return m, tea.Quit
 		case "help":
+			if hasFlag("--json") {
+				printHelpJSON(append(append([]commandInfo{}, commandList...), hiddenCommandList...))
+			}
 			return m, tea.Quit
 		case "account":
-			if !isOnline() {
+			if args := positionalArgs(); len(args) > 0 && args[0] == "switch" {
+				return m.startAccountSwitch()
+			}
+			if args := positionalArgs(); len(args) > 0 && args[0] == "usage" {
+				if !isOnline() {
+					return m, func() tea.Msg {
+						return errorScreenMsg{errorMessage: offlineMessage}
+					}
+				}
+				return m, performAccountUsage
+			}
+			if !isOnline() && !hasFlag("--token-expiry") {
 				return m, func() tea.Msg {
 					return errorScreenMsg{errorMessage: offlineMessage}
 				}
 			}
 			return m, performAccount
+		case "profile":
+			args := positionalArgs()
+			switch {
+			case len(args) == 0 || args[0] == "list":
+				return m, performProfileList
+			case args[0] == "use" && len(args) > 1:
+				return m, func() tea.Msg {
+					return performProfileSwitch(args[1])
+				}
+			default:
+				return m, usage("Usage: basic profile [list | use <name>]")
+			}
 		case "login":
 			if !isOnline() {
 				return m, func() tea.Msg {
@@ -844,53 +1432,111 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				}
 			}
 			return m, performLogin
+		case "reauth":
+			if !isOnline() {
+				return m, func() tea.Msg {
+					return errorScreenMsg{errorMessage: offlineMessage}
+				}
+			}
+			return m, performReauth
+		case "token":
+			if args := positionalArgs(); len(args) == 0 || args[0] != "refresh" {
+				return m, usage("Usage: basic token refresh")
+			}
+			if !isOnline() {
+				return m, func() tea.Msg {
+					return errorScreenMsg{errorMessage: offlineMessage}
+				}
+			}
+			return m, performTokenRefresh
 		case "logout":
 			return m, performLogout
 		case "status":
-			token, err := loadToken()
-			if err != nil || token == nil {
-				return m, func() tea.Msg {
-					return errorScreenMsg{errorMessage: loggedOutMessage}
+			if !hasFlag("--no-fetch") {
+				if token, err := loadToken(); err != nil || token == nil {
+					return m, func() tea.Msg {
+						return errorScreenMsg{errorMessage: loggedOutError(err)}
+					}
 				}
 			}
 
 			m.state = stateStatus
-			fmt.Println("Checking status...")
+			if !hasFlag("--json") {
+				fmt.Println("Checking status...")
+			}
 			return m, checkStatusCmd
 		case "push":
 			token, err := loadToken()
 			if err != nil || token == nil {
 				return m, func() tea.Msg {
-					return errorScreenMsg{errorMessage: loggedOutMessage}
+					return errorScreenMsg{errorMessage: loggedOutError(err)}
 				}
 			}
 
 			m.showMessages = true
-			m.messages = append(m.messages, "Pushing schema...")
+			if hasFlag("--dry-run") {
+				m.messages = append(m.messages, "Checking what a push would change...")
+			} else {
+				m.messages = append(m.messages, "Pushing schema...")
+			}
 			return m, pushSchemaCmd
 		case "pull":
 			token, err := loadToken()
 			if err != nil || token == nil {
 				return m, func() tea.Msg {
-					return errorScreenMsg{errorMessage: loggedOutMessage}
+					return errorScreenMsg{errorMessage: loggedOutError(err)}
 				}
 			}
 
 			m.showMessages = true
+			if hasFlag("--check") {
+				return m, pullCheckCmd
+			}
 			return m, pullSchemaCmd
 		case "projects":
 			if !isOnline() {
 				return m, func() tea.Msg {
+					if cache, ok := loadProjectsCache(); ok && len(positionalArgs()) == 0 {
+						return projectsMsg{projects: cache.Projects, cachedAt: cache.FetchedAt}
+					}
 					return errorScreenMsg{errorMessage: offlineMessage}
 				}
 			}
 
-			return m, func() tea.Msg {
-				token, err := loadToken()
+			if args := positionalArgs(); len(args) > 0 {
+				switch args[0] {
+				case "open":
+					return m, performProjectsOpen
+				case "search":
+					return m, performProjectsSearch
+				case "delete":
+					if len(args) < 2 {
+						if !isInteractive() {
+							return m, usage("Usage: basic projects delete <project_id>")
+						}
+						return m, pickProjectToDelete
+					}
+					return m, func() tea.Msg {
+						return performProjectsDelete(args[1])
+					}
+				}
+			}
+
+			return m, func() tea.Msg {
+				token, err := loadToken()
 				if err != nil || token == nil {
-					return errorScreenMsg{errorMessage: loggedOutMessage}
+					return errorScreenMsg{errorMessage: loggedOutError(err)}
 				}
-				return getProjectsMsg(token)
+				msg := getProjectsMsg(token)
+				if projects, ok := msg.(projectsMsg); ok && projects.err == nil {
+					saveProjectsCache(projects.projects)
+					projects.syncStatuses = localSyncStatuses()
+					if !hasFlag("--json", "--csv", "--fields") {
+						projects.userID, _ = getUserID(token)
+					}
+					return projects
+				}
+				return msg
 			}
 		case "init":
 			if !isOnline() {
@@ -902,30 +1548,136 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			token, err := loadToken()
 			if err != nil || token == nil {
 				return m, func() tea.Msg {
-					return errorScreenMsg{errorMessage: loggedOutMessage}
+					return errorScreenMsg{errorMessage: loggedOutError(err)}
 				}
 			}
 
-			if _, err := os.Stat("basic.config.ts"); err == nil {
-				return m, func() tea.Msg {
-					m.state = stateError
-					return errorScreenMsg{errorMessage: "basic.config.ts already exists in this directory"}
+			if configOut := flagValue("--config-out"); configOut != "" {
+				if err := checkConfigOutPath(configOut); err != nil {
+					return m, func() tea.Msg {
+						return errorScreenMsg{errorMessage: err.Error()}
+					}
 				}
-			}
-			if _, err := os.Stat("basic.config.js"); err == nil {
+				if _, err := os.Stat(configOut); err == nil {
+					return m, func() tea.Msg {
+						return errorScreenMsg{errorMessage: configOut + " already exists"}
+					}
+				}
+			} else if existing, err := findConfigFile(); err == nil {
 				return m, func() tea.Msg {
-					return errorScreenMsg{errorMessage: "basic.config.js already exists in this directory"}
+					return errorScreenMsg{errorMessage: existing + " already exists in this directory"}
 				}
 			}
 
 			return NewFormModel(), func() tea.Msg {
 				return projectFormMsg{projectName: "test"}
 			}
+		case "schema":
+			return m.runSchemaCommand()
+		case "generate":
+			return m.runGenerateCommand()
+		case "watch":
+			return m.startConfigWatch()
+		case "env":
+			return m, performEnv
+		case "migrate":
+			token, err := loadToken()
+			if err != nil || token == nil {
+				return m, func() tea.Msg {
+					return errorScreenMsg{errorMessage: loggedOutError(err)}
+				}
+			}
+			if !isOnline() {
+				return m, func() tea.Msg {
+					return errorScreenMsg{errorMessage: offlineMessage}
+				}
+			}
+			return m, performMigrate
+		case "keys":
+			token, err := loadToken()
+			if err != nil || token == nil {
+				return m, func() tea.Msg {
+					return errorScreenMsg{errorMessage: loggedOutError(err)}
+				}
+			}
+			if !isOnline() {
+				return m, func() tea.Msg {
+					return errorScreenMsg{errorMessage: offlineMessage}
+				}
+			}
+			return m, performKeys
+		case "team":
+			token, err := loadToken()
+			if err != nil || token == nil {
+				return m, func() tea.Msg {
+					return errorScreenMsg{errorMessage: loggedOutError(err)}
+				}
+			}
+			if !isOnline() {
+				return m, func() tea.Msg {
+					return errorScreenMsg{errorMessage: offlineMessage}
+				}
+			}
+			return m, performTeam
+		case "validate":
+			if hasFlag("--watch") {
+				return m.startValidateWatch()
+			}
+			return m, performValidate
+		case "history":
+			token, err := loadToken()
+			if err != nil || token == nil {
+				return m, func() tea.Msg {
+					return errorScreenMsg{errorMessage: loggedOutError(err)}
+				}
+			}
+			return m, performHistory
+		case "diff":
+			token, err := loadToken()
+			if err != nil || token == nil {
+				return m, func() tea.Msg {
+					return errorScreenMsg{errorMessage: loggedOutError(err)}
+				}
+			}
+			if !isOnline() {
+				return m, func() tea.Msg {
+					return errorScreenMsg{errorMessage: offlineMessage}
+				}
+			}
+			return m, performDiff
+		case "data":
+			token, err := loadToken()
+			if err != nil || token == nil {
+				return m, func() tea.Msg {
+					return errorScreenMsg{errorMessage: loggedOutError(err)}
+				}
+			}
+			if len(positionalArgs()) == 0 && !isInteractive() {
+				return m, usage("Usage: basic data <table>")
+			}
+			if !isOnline() {
+				return m, func() tea.Msg {
+					return errorScreenMsg{errorMessage: offlineMessage}
+				}
+			}
+			if isDataRecordCommand() {
+				return m, performDataRecord
+			}
+			if hasFlag("--json") {
+				return m, performDataJSON
+			}
+			return m, loadDataSchema
 		case "debug":
 			configDir := filepath.Join(os.Getenv("HOME"), basicCliDirName)
 			fmt.Printf("Basic CLI config directory: %s\n", configDir)
 			return m, tea.Quit
 		case "update":
+			if channel := flagValue("--channel"); channel != "" {
+				if err := saveUpdateChannel(channel); err != nil {
+					return m, usage(err.Error())
+				}
+				fmt.Printf("Using the %s update channel\n", channel)
+			}
 			if !isOnline() {
 				return m, func() tea.Msg {
 					return errorScreenMsg{errorMessage: offlineMessage}
@@ -933,27 +1685,44 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 			latestVersion, latestErr := checkLatestRelease()
 			if latestErr != nil {
-				fmt.Printf("Oopsy - error checking for updates: %v\n", latestErr)
-				return m, tea.Quit
+				return m, func() tea.Msg {
+					return errorScreenMsg{errorMessage: fmt.Sprintf("error checking for updates: %v", latestErr)}
+				}
 			} else if latestVersion == version {
 				fmt.Printf("You are already running the latest version!\n")
 				return m, tea.Quit
 			}
 
-			cmd := exec.Command("npm", "update", "-g", "@basictech/cli")
+			command := updateCommand(installMethod(), latestVersion)
+			cmd := exec.Command(command[0], command[1:]...)
 			_, err := cmd.CombinedOutput()
 			if err != nil {
-				fmt.Printf("Error updating CLI: %v\n try running 'npm update -g @basictech/cli' or visit https://docs.basic.tech/", err)
-			} else {
-				fmt.Println("Update successful!")
+				return m, func() tea.Msg {
+					return errorScreenMsg{errorMessage: fmt.Sprintf("error updating CLI: %v\ntry running '%s' or visit https://docs.basic.tech/", err, strings.Join(command, " "))}
+				}
 			}
+			fmt.Println("Update successful!")
 			return m, tea.Quit
 		case "version":
+			if hasFlag("--json") {
+				printVersionJSON()
+			}
+			return m, tea.Quit
+		case "feedback":
+			return m, performFeedback
+		case "doctor":
+			fmt.Println("Checking your setup...")
+			return m, performDoctor
+		case "logo":
 			return m, tea.Quit
 		default:
 			suggestions := findSimilarCommands(m.choice)
 			m.state = stateUnknown
 			m.suggestions = suggestions
+			m.exitCode = exitUsage
+			if hasFlag("--json") {
+				printJSONError(unknownCommandError(m.choice, suggestions), "usage")
+			}
 			return m, tea.Quit
 		}
 	case stateStatus:
@@ -962,14 +1731,63 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 		switch msg := msg.(type) {
 		case statusMsg:
+			switch msg.status {
+			case "conflict", "invalid":
+				m.exitCode = exitSchema
+			case "":
+				// no config, or the status couldn't be checked
+				m.exitCode = exitError
+			}
+			if hasFlag("--json") {
+				printStatusJSON(msg)
+				return m, tea.Quit
+			}
 			m.statusMessages = append(m.statusMessages, msg.text)
+			if hasFlag("--explain") {
+				m.statusMessages = append(m.statusMessages, explainStatus(msg)...)
+			}
+			if hasFlag("--summary") {
+				status := msg.status
+				if status == "" {
+					status = "unknown"
+				}
+				m.statusMessages = append(m.statusMessages, resultSummaryLine(status, msg.projectID, msg.localVersion, msg.errors))
+			}
 			return m, tea.Quit
 		case statusErrorMsg:
+			m.exitCode = exitCodeFor(errorCode(msg.err.Error()))
+			if hasFlag("--json") {
+				printJSONError(msg.err.Error(), "")
+				return m, tea.Quit
+			}
 			m.statusError = msg.err
 			m.statusLoading = false
 			return m, tea.Quit
 		}
 
+	case stateWatching:
+		switch msg := msg.(type) {
+		case tea.KeyMsg:
+			switch msg.String() {
+			case "ctrl+c", "esc", "q":
+				return m, tea.Quit
+			}
+		case fileWatchMsg:
+			if msg.clear {
+				m.messages = nil
+			}
+			m.messages = append(m.messages, msg.lines...)
+			if len(m.messages) > maxWatchMessages {
+				m.messages = m.messages[len(m.messages)-maxWatchMessages:]
+			}
+			return m, fileWatchCmd(msg)
+		case errorScreenMsg:
+			m.exitCode = exitError
+			m.state = stateError
+			m.errorMessage = msg.errorMessage
+			return m, tea.Quit
+		}
+
 	case stateSuccess:
 		if msg, ok := msg.(tea.KeyMsg); ok && msg.Type == tea.KeyEnter {
 			return m, tea.Quit
@@ -991,10 +1809,47 @@ type statusMsg struct {
 	status    string
 	schema    string
 	projectID string
+
+	localVersion  float64
+	remoteVersion float64
+
+	// set when a "behind" schema also has local edits that a pull would discard
+	localEdits bool
+
+	// number of validation errors when the status is "invalid"
+	errors int
+}
+
+// statusJSON is what 'basic status --json' prints
+type statusJSON struct {
+	ProjectID     string  `json:"project_id"`
+	Status        string  `json:"status"`
+	LocalVersion  float64 `json:"local_version"`
+	RemoteVersion float64 `json:"remote_version"`
+	LocalEdits    bool    `json:"local_edits,omitempty"`
+	Errors        int     `json:"errors,omitempty"`
+	Message       string  `json:"message"`
+}
+
+func printStatusJSON(msg statusMsg) {
+	status := msg.status
+	if status == "" {
+		status = "unknown"
+	}
+	out, _ := json.MarshalIndent(statusJSON{
+		ProjectID:     msg.projectID,
+		Status:        status,
+		LocalVersion:  msg.localVersion,
+		RemoteVersion: msg.remoteVersion,
+		LocalEdits:    msg.localEdits,
+		Errors:        msg.errors,
+		Message:       msg.text,
+	}, "", "  ")
+	fmt.Println(string(out))
 }
 
 func isOnline() bool {
-	_, err := http.Get("https://api.basic.tech/")
+	_, err := apiClient().Get("https://api.basic.tech/")
 	return err == nil
 }
 
@@ -1010,9 +1865,13 @@ func (m model) View() string {
 		}
 
 		if m.statusError != nil {
-			return lipgloss.NewStyle().
+			view := lipgloss.NewStyle().
 				Foreground(lipgloss.Color("9")).
 				Render(fmt.Sprintf("Error: %v", m.statusError))
+			if hasFlag("--summary") {
+				view += "\n" + resultSummaryLine("error", "", 0, 1)
+			}
+			return view
 		}
 
 		for _, msg := range m.statusMessages {
@@ -1028,6 +1887,24 @@ func (m model) View() string {
 		return s.String()
 	}
 
+	if m.state == stateWatching {
+		var s strings.Builder
+		s.WriteString(m.watchHeader + "\n\n")
+		for _, msg := range m.messages {
+			if strings.HasPrefix(msg, " -") {
+				s.WriteString(lipgloss.NewStyle().
+					Foreground(lipgloss.Color("9")).
+					Render(msg) + "\n")
+			} else {
+				s.WriteString(msg + "\n")
+			}
+		}
+		s.WriteString("\n" + lipgloss.NewStyle().
+			Foreground(lipgloss.Color("240")).
+			Render("esc to stop watching"))
+		return s.String()
+	}
+
 	if m.form != nil {
 		return "\n" + m.form.View() + "\n\n" +
 			lipgloss.NewStyle().
@@ -1094,18 +1971,30 @@ func (m model) View() string {
 		var b string
 		b += "Usage: basic <command> [arguments]\n\n"
 		b += "Commands:\n"
-		b += "  account - Show account information\n"
-		b += "  login - login with your basic account\n"
-		b += "  logout - logout from your basic account\n"
-		b += "  status - Show schema status in current project\n"
-		b += "  push - Push schema to remote\n"
-		b += "  pull - Pull schema from remote\n"
-		b += "  projects - list your projects\n"
-		b += "  init - Create a new project or import an existing project\n"
-		b += "  version - Show CLI version\n"
-		b += "  update - Update CLI to the latest version\n"
-		b += "  debug - Show Basic config directory location\n"
+		for _, c := range commandList {
+			b += fmt.Sprintf("  %s - %s\n", c.name, c.description)
+		}
+		if hasFlag("--all") {
+			b += "\nHidden commands:\n"
+			for _, c := range hiddenCommandList {
+				b += fmt.Sprintf("  %s - %s\n", c.name, c.description)
+			}
+		}
+		aliases := make([]string, 0, len(commandAliases))
+		for alias := range commandAliases {
+			aliases = append(aliases, alias)
+		}
+		sort.Strings(aliases)
+		b += "\nAliases:\n"
+		for _, alias := range aliases {
+			b += fmt.Sprintf("  %s - %s\n", alias, commandAliases[alias])
+		}
+		b += "\nGlobal flags:\n"
+		for _, f := range globalFlagList {
+			b += fmt.Sprintf("  %s - %s\n", f.name, f.description)
+		}
 
+		b += "\nExit codes: 0 success, 1 error, 2 usage error, 3 not logged in, 4 offline, 5 schema invalid or conflicting\n"
 		b += "\nIf you are having trouble, please visit https://docs.basic.tech\n"
 		return b
 	}
@@ -1130,7 +2019,12 @@ type pullSchemaConfirmMsg struct {
 
 func pullSchemaConfirmCmd(projectID string) tea.Msg {
 
-	schema, err := getProjectSchema(projectID)
+	schemas, err := getProjectSchemas(projectID)
+	if err != nil {
+		fmt.Println("Error pulling schema:", err)
+		return pullSchemaMsg{success: false, message: "Error pulling schema"}
+	}
+	schema, err := selectProjectSchema(schemas)
 	if err != nil {
 		fmt.Println("Error pulling schema:", err)
 		return pullSchemaMsg{success: false, message: "Error pulling schema"}
@@ -1139,17 +2033,101 @@ func pullSchemaConfirmCmd(projectID string) tea.Msg {
 		return pullSchemaMsg{success: false, message: "No schema found for project"}
 	}
 
+	// the API can return more than one schema record; make it visible which one we used
+	if len(schemas) > 1 {
+		index, _ := schemaIndex()
+		fmt.Printf("Project has %d schema records, using record %d (choose another with --schema-index <n>)\n", len(schemas), index)
+	}
+
+	// keep a copy of what we're about to overwrite
+	backupPath := ""
+	if configFile, err := findConfigFile(); err == nil {
+		backupPath, err = backupConfigFile(configFile, projectID)
+		if err != nil {
+			fmt.Println("Error backing up config:", err)
+			return pullSchemaMsg{success: false, message: "Error backing up config, schema not pulled"}
+		}
+	}
+
 	err = saveSchemaToConfig(schema)
 	if err != nil {
 		fmt.Println("Error saving schema to config:", err)
 		return pullSchemaMsg{success: false, message: "Error saving schema to config"}
 	}
 
+	saveProjectLink(projectID, "")
+
+	if backupPath != "" {
+		return pullSchemaMsg{success: true, message: fmt.Sprintf("Schema pulled successfully! Previous config saved to %s", backupPath)}
+	}
 	return pullSchemaMsg{success: true, message: "Schema pulled successfully!"}
 
 }
 
+// pullIntoConfigWithoutSchema pulls the remote schema into a config that
+// doesn't declare one yet, finding the project from the config block or the
+// directory's project link
+func pullIntoConfigWithoutSchema() tea.Msg {
+	projectID, err := configProjectID()
+	if err != nil {
+		if link, ok := getProjectLink(); ok {
+			projectID = link.ProjectID
+		}
+	}
+	if override := projectOverride(); override != "" {
+		projectID = override
+	}
+	if projectID == "" {
+		return pullSchemaMsg{success: false, message: "Your config has no schema and no project_id, so there's nothing to pull into.\n" +
+			"Add a project_id to the config block (e.g. export const config = { project_id: \"<id>\" }) or run 'basic init'"}
+	}
+
+	fmt.Printf("Your config has no schema yet, adding the schema of project %s\n", projectID)
+	return pullSchemaConfirmCmd(projectID)
+}
+
+// pullIntoNewConfig implements 'basic pull --project <id>' in a directory
+// without a config, creating one with the project's schema
+func pullIntoNewConfig(projectID string) tea.Msg {
+	schema, err := getProjectSchema(projectID)
+	if err != nil {
+		return pullSchemaMsg{success: false, message: fmt.Sprintf("Error pulling schema: %v", err)}
+	}
+	if schema == "" {
+		return pullSchemaMsg{success: false, message: fmt.Sprintf("Project %s has no schema yet, run 'basic init' to start one", projectID)}
+	}
+
+	name := projectID
+	if p, ok := findProject(projectID, true); ok && p.Name != "" {
+		name = p.Name
+	}
+	option := detectConfigLanguage()
+	if err := createConfigFile(name, projectID, option, schema); err != nil {
+		return pullSchemaMsg{success: false, message: fmt.Sprintf("Error creating config: %v", err)}
+	}
+	saveProjectLink(projectID, "")
+
+	filename := configFormatNamed(option).filename
+	if configOut := flagValue("--config-out"); configOut != "" {
+		filename = configOut
+	}
+	return pullSchemaMsg{success: true, message: fmt.Sprintf("Created %s with the schema of %s", filename, describeProject(projectID, false))}
+}
+
 func pullSchemaCmd() tea.Msg {
+	if override := projectOverride(); override != "" {
+		if _, err := findConfigFile(); err != nil {
+			return pullIntoNewConfig(override)
+		}
+	}
+
+	// a config without a schema block has nothing to compare or overwrite,
+	// so the remote schema is simply added to it
+	if _, err := readSchemaFromConfig(); errors.Is(err, errNoSchemaFound) {
+		if _, err := findConfigFile(); err == nil {
+			return pullIntoConfigWithoutSchema()
+		}
+	}
 
 	m := checkStatusCmd()
 	if m, ok := m.(statusMsg); ok {
@@ -1157,6 +2135,8 @@ func pullSchemaCmd() tea.Msg {
 			return pullSchemaMsg{success: true, message: "Schema is up to date!"}
 		} else if m.status == "conflict" {
 			return pullSchemaConfirmMsg{success: false, message: "Conflicts found - your local schema is different from remote schema.", projectID: m.projectID}
+		} else if m.status == "behind" && m.localEdits {
+			return pullSchemaConfirmMsg{success: false, message: "Your schema is out of date, but it has local changes that pulling will discard. Pull anyway?", projectID: m.projectID}
 		} else if m.status == "behind" {
 			return pullSchemaConfirmMsg{success: false, message: "Your schema is out of date. Do you want to pull the latest version?", projectID: m.projectID}
 		} else if m.status == "valid" {
@@ -1167,9 +2147,86 @@ func pullSchemaCmd() tea.Msg {
 	return pullSchemaMsg{success: false, message: m.(statusMsg).text}
 }
 
+type pullCheckMsg struct {
+	drifted bool
+	lines   []string
+}
+
+// pullCheckCmd reports whether the local config has drifted from the remote
+// schema without writing anything, for use in CI
+func pullCheckCmd() tea.Msg {
+	schema, err := readLocalSchema()
+	if err != nil {
+		return pullCheckMsg{drifted: true, lines: []string{fmt.Sprintf("Error reading schema: %v", err)}}
+	}
+
+	localSchema, err := parseSchemaJSON(schema)
+	if err != nil {
+		return pullCheckMsg{drifted: true, lines: []string{err.Error()}}
+	}
+
+	projectID, ok := localSchema["project_id"].(string)
+	if !ok {
+		return pullCheckMsg{drifted: true, lines: []string{"No project ID found in schema"}}
+	}
+
+	remote, err := getProjectSchema(projectID)
+	if err != nil {
+		return pullCheckMsg{drifted: true, lines: []string{fmt.Sprintf("Error fetching remote schema: %v", err)}}
+	}
+	if remote == "" {
+		return pullCheckMsg{drifted: true, lines: []string{"No remote schema found for project " + projectID}}
+	}
+
+	remoteSchema, err := parseSchemaJSON(remote)
+	if err != nil {
+		return pullCheckMsg{drifted: true, lines: []string{err.Error()}}
+	}
+
+	changes := filterIgnoredChanges(diffSchemas(remoteSchema, localSchema), schemaIgnorePaths())
+	if len(changes) == 0 {
+		return pullCheckMsg{lines: []string{"Local schema matches the remote schema."}}
+	}
+
+	lines := []string{
+		"Local schema has drifted from the remote schema (+ only in local, - only in remote):",
+		"",
+	}
+	for _, c := range changes {
+		lines = append(lines, "  "+formatSchemaChange(c))
+	}
+	lines = append(lines, "", "Run 'basic pull' to update your local schema, or 'basic push' to publish local changes.")
+	return pullCheckMsg{drifted: true, lines: lines}
+}
+
 type pushSchemaMsg struct {
 	success bool
 	message string
+	// exit code when the push failed, exitError if unset
+	exitCode int
+}
+
+// confirmMsg asks the user to confirm an action before running it
+type confirmMsg struct {
+	title       string
+	description string
+	affirmative string
+	// run is called once confirmed, otherwise cancelled is shown
+	run       func() tea.Msg
+	cancelled string
+	// when set, the user has to type this (e.g. a project name) to confirm
+	typeToConfirm string
+	// when set, declining runs this instead of showing cancelled, for
+	// confirmations that skip one of several steps
+	declined func() tea.Msg
+}
+
+// projectPickMsg asks the user to pick one of projects, then runs run with
+// its ID
+type projectPickMsg struct {
+	title    string
+	projects []project
+	run      func(projectID string) tea.Msg
 }
 
 func pushSchemaCmd() tea.Msg {
@@ -1178,13 +2235,21 @@ func pushSchemaCmd() tea.Msg {
 
 	if m, ok := m.(statusMsg); ok {
 		if m.status == "valid" {
-			success, err := pushProjectSchema(m.schema)
-			if err != nil {
-				fmt.Println("Error pushing schema:", err)
-				return pushSchemaMsg{success: false, message: "Error pushing schema"}
+			if hasFlag("--dry-run") {
+				return pushDryRun(m)
 			}
-
-			return pushSchemaMsg{success: success, message: "Schema pushed successfully!"}
+			if isInteractive() && !assumeYes() {
+				return confirmMsg{
+					title:       "Push this schema?",
+					description: pushSummary(m),
+					affirmative: "Yes, push schema",
+					run:         func() tea.Msg { return pushValidSchema(m) },
+					cancelled:   "Push cancelled",
+				}
+			}
+			return pushValidSchema(m)
+		} else if m.status == "conflict" || m.status == "invalid" {
+			return pushSchemaMsg{success: false, message: m.text, exitCode: exitSchema}
 		} else {
 			return pushSchemaMsg{success: false, message: m.text}
 		}
@@ -1193,57 +2258,268 @@ func pushSchemaCmd() tea.Msg {
 	return pushSchemaMsg{success: false, message: "Error checking schema status"}
 }
 
-func checkStatusCmd() tea.Msg {
-	// Check authentication
-	token, err := loadToken()
+// pushValidSchema publishes a schema that checkStatusCmd reported as valid
+func pushValidSchema(m statusMsg) tea.Msg {
+	success, err := pushProjectSchema(m.schema)
 	if err != nil {
-		return statusErrorMsg{err: fmt.Errorf("not logged in")}
+		fmt.Println("Error pushing schema:", err)
+		return pushSchemaMsg{success: false, message: "Error pushing schema"}
 	}
-	if !token.Valid() {
-		return statusErrorMsg{err: fmt.Errorf("token has expired")}
+
+	if hasFlag("--confirm-remote", "--watch-version") {
+		remoteVersion, err := confirmRemoteVersion(m.projectID, m.localVersion)
+		if err != nil {
+			return pushSchemaMsg{success: false, message: fmt.Sprintf("Schema pushed, but could not confirm the remote version: %v", err)}
+		}
+		if remoteVersion != m.localVersion {
+			return pushSchemaMsg{success: false, message: fmt.Sprintf("Schema pushed, but remote is still at version %.0f (expected %.0f)", remoteVersion, m.localVersion)}
+		}
+		return pushSchemaMsg{success: success, message: fmt.Sprintf("Schema pushed successfully! Remote is now at version %.0f", remoteVersion)}
 	}
 
-	// Read and validate schema
-	schema, err := readSchemaFromConfig()
+	return pushSchemaMsg{success: success, message: "Schema pushed successfully!"}
+}
+
+// pushSummary describes what a push is about to publish
+func pushSummary(m statusMsg) string {
+	summary := fmt.Sprintf("Project: %s\nVersion: %.0f -> %.0f", m.projectID, m.remoteVersion, m.localVersion)
+
+	remote, err := getProjectSchema(m.projectID)
+	if err != nil || remote == "" {
+		return summary
+	}
+	remoteSchema, err := parseSchemaJSON(remote)
 	if err != nil {
-		return statusMsg{text: strings.Join([]string{
-			fmt.Sprintf("Error reading schema: %v", err),
-			"Please make sure a basic config file exists and is valid",
-			"you can also run 'basic init' to create a new project or import an existing project",
-		}, "\n")}
+		return summary
 	}
-	if schema == "" {
-		return statusMsg{text: "No schema found in config files"}
+	localSchema, err := parseSchemaJSON(m.schema)
+	if err != nil {
+		return summary
 	}
 
-	// Parse schema JSON
-	var schemaData map[string]interface{}
-	if err := json.Unmarshal([]byte(schema), &schemaData); err != nil {
-		return statusMsg{text: fmt.Sprintf("Error parsing schema: %v", err)}
-	}
+	tables := changedTables(diffSchemas(remoteSchema, localSchema))
+	return summary + fmt.Sprintf("\nChanged tables: %d", len(tables))
+}
 
-	// Get project ID
-	projectID, ok := schemaData["project_id"].(string)
-	if !ok {
-		return statusMsg{text: "No project ID found in schema"}
+// pushDryRunJSON is what 'basic push --dry-run --json' prints
+type pushDryRunJSON struct {
+	ProjectID       string           `json:"project_id"`
+	FromVersion     float64          `json:"from_version"`
+	ToVersion       float64          `json:"to_version"`
+	Changes         []schemaChange   `json:"changes"`
+	BreakingChanges []breakingChange `json:"breaking_changes,omitempty"`
+}
+
+// pushDryRun shows what pushing a valid schema would change remotely,
+// without pushing it
+func pushDryRun(m statusMsg) tea.Msg {
+	localSchema, err := parseSchemaJSON(m.schema)
+	if err != nil {
+		return pushSchemaMsg{success: false, message: err.Error()}
+	}
+	remoteSchema := map[string]interface{}{}
+	remote, err := getProjectSchema(m.projectID)
+	if err != nil {
+		return pushSchemaMsg{success: false, message: fmt.Sprintf("Error fetching remote schema: %v", err)}
+	}
+	if remote != "" {
+		if remoteSchema, err = parseSchemaJSON(remote); err != nil {
+			return pushSchemaMsg{success: false, message: err.Error()}
+		}
 	}
 
-	messages := []string{fmt.Sprintf("Project ID: %s", projectID)}
+	changes := detectRenamedFields(filterIgnoredChanges(diffSchemas(remoteSchema, localSchema), schemaIgnorePaths()))
+	breaking := breakingChanges(remoteSchema, localSchema)
 
-	// Get and validate latest schema
-	latestSchema, err := getProjectSchema(projectID)
-	if err != nil && latestSchema == "" {
-		messages = append(messages, fmt.Sprintf("Error fetching latest schema: %v", err))
-		return statusMsg{text: strings.Join(messages, "\n"), schema: schema, projectID: projectID}
+	if hasFlag("--json") {
+		if changes == nil {
+			changes = []schemaChange{}
+		}
+		out, _ := json.MarshalIndent(pushDryRunJSON{
+			ProjectID:       m.projectID,
+			FromVersion:     m.remoteVersion,
+			ToVersion:       m.localVersion,
+			Changes:         changes,
+			BreakingChanges: breaking,
+		}, "", "  ")
+		fmt.Println(string(out))
+		return tea.Quit()
 	}
 
-	// Create empty schema if none exists
-	if latestSchema == "" {
-		latestSchema = fmt.Sprintf(`{
-			"project_id": "%s",
-			"version": 0,
-			"tables": {}
-		}`, projectID)
+	lines := []string{
+		fmt.Sprintf("Project: %s", m.projectID),
+		fmt.Sprintf("Version: %.0f -> %.0f", m.remoteVersion, m.localVersion),
+		"",
+	}
+	if len(changes) == 0 {
+		lines = append(lines, "No tables or fields would change.")
+	} else {
+		lines = append(lines, fmt.Sprintf("Would change %s in %s (+ added, - removed, ~ modified, > renamed):",
+			countNoun(len(changes), "path"), countNoun(len(changedTables(changes)), "table")))
+		for _, c := range changes {
+			lines = append(lines, "  "+renderSchemaChange(c))
+		}
+	}
+	if len(breaking) > 0 {
+		lines = append(lines, "", "Breaking changes for existing data:")
+		for _, c := range breaking {
+			lines = append(lines, "  ! "+c.Reason)
+		}
+	}
+	lines = append(lines, "", "Dry run: nothing was pushed. Run 'basic push' without --dry-run to publish.")
+	return pushSchemaMsg{success: true, message: strings.Join(lines, "\n")}
+}
+
+// assumeYes reports whether --yes/-y was passed to answer every confirmation
+// with yes. Destructive confirmations (like overwriting the local schema on
+// pull) are only skipped this way, never by --ci or a missing terminal.
+func assumeYes() bool {
+	return hasFlag("--yes", "-y")
+}
+
+// isInteractive reports whether we can prompt the user, i.e. we're attached
+// to a terminal and not running with --ci or --json
+func isInteractive() bool {
+	if hasFlag("--ci", "--json") {
+		return false
+	}
+	return isatty.IsTerminal(os.Stdin.Fd()) && isatty.IsTerminal(os.Stdout.Fd())
+}
+
+// runsHeadless reports whether command runs without reading keys or drawing
+// to a terminal: when there's no terminal, as in CI, when stdin holds the
+// schema, or for output meant for scripts
+func runsHeadless(command string) bool {
+	if !isatty.IsTerminal(os.Stdin.Fd()) || !isatty.IsTerminal(os.Stdout.Fd()) {
+		return true
+	}
+	if quietValidate(command) || (isValidateCommand(command) && hasFlag("--all")) {
+		return true
+	}
+	return readsSchemaFromStdin() || hasFlag("--ci", "--json", "--check", "--summary")
+}
+
+// confirmRemoteVersion re-fetches the remote schema until it reaches version,
+// since the backend can take a moment to reflect a push. It returns the last
+// remote version seen.
+func confirmRemoteVersion(projectID string, version float64) (float64, error) {
+	const attempts = 5
+
+	var remoteVersion float64
+	for i := 0; i < attempts; i++ {
+		if i > 0 {
+			time.Sleep(time.Duration(i) * time.Second)
+		}
+
+		// the push just changed the remote schema, so always ask again
+		forgetFetchedSchemas()
+		remote, err := getProjectSchema(projectID)
+		if err != nil {
+			return 0, err
+		}
+		if remote == "" {
+			continue
+		}
+
+		remoteSchema, err := parseSchemaJSON(remote)
+		if err != nil {
+			return 0, err
+		}
+		remoteVersion, err = readSchemaVersion(remoteSchema)
+		if err != nil {
+			continue
+		}
+		if remoteVersion == version {
+			return remoteVersion, nil
+		}
+	}
+	return remoteVersion, nil
+}
+
+func checkStatusCmd() tea.Msg {
+	// --no-fetch compares against the last remote schema we saw, without any network calls
+	noFetch := hasFlag("--no-fetch")
+
+	// Check authentication
+	if !noFetch {
+		token, err := loadToken()
+		if errors.Is(err, errNoRefreshToken) {
+			return statusErrorMsg{err: err}
+		}
+		if err != nil {
+			return statusErrorMsg{err: fmt.Errorf("not logged in")}
+		}
+		if !token.Valid() {
+			return statusErrorMsg{err: fmt.Errorf("token has expired")}
+		}
+	}
+
+	// Read and validate schema
+	schema, err := readLocalSchema()
+	if override := projectOverride(); override != "" && errors.Is(err, errNoSchemaFound) {
+		return remoteOnlyStatus(override, noFetch)
+	}
+	if err != nil {
+		messages := []string{
+			fmt.Sprintf("Error reading schema: %v", err),
+			"Please make sure a basic config file exists and is valid",
+			"you can also run 'basic init' to create a new project or import an existing project",
+		}
+		if link, ok := getProjectLink(); ok {
+			messages = append(messages, "", fmt.Sprintf("This directory was linked to project %s", link.describe()),
+				"run 'basic init' and choose 'Use existing project' to restore its config")
+		}
+		return statusMsg{text: strings.Join(messages, "\n")}
+	}
+	if schema == "" {
+		return statusMsg{text: "No schema found in config files"}
+	}
+
+	// Parse schema JSON
+	var schemaData map[string]interface{}
+	if err := json.Unmarshal([]byte(schema), &schemaData); err != nil {
+		return statusMsg{text: fmt.Sprintf("Error parsing schema: %v", err)}
+	}
+
+	// Get project ID
+	projectID, ok := schemaData["project_id"].(string)
+	if !ok {
+		return statusMsg{text: "No project ID found in schema"}
+	}
+
+	messages := []string{fmt.Sprintf("Project: %s", describeProject(projectID, !noFetch))}
+	if account, ok := loadAccountInfo(); ok && account.Email != "" {
+		messages = append(messages, fmt.Sprintf("Account: %s", account.Email))
+	}
+
+	// Get and validate latest schema
+	var latestSchema string
+	if noFetch {
+		entry, ok := loadRemoteSchemaCache()[projectID]
+		if !ok {
+			messages = append(messages, "No cached remote schema for this project yet. Run 'basic status' once while online.")
+			return statusMsg{text: strings.Join(messages, "\n"), schema: schema, projectID: projectID}
+		}
+		latestSchema = entry.Schema
+		messages = append(messages, fmt.Sprintf("Using the cached remote schema from %s, it may be stale",
+			entry.FetchedAt.Local().Format("2006-01-02 15:04")))
+	} else {
+		latestSchema, err = getProjectSchema(projectID)
+		if err != nil && latestSchema == "" {
+			messages = append(messages, fmt.Sprintf("Error fetching latest schema: %v", err))
+			return statusMsg{text: strings.Join(messages, "\n"), schema: schema, projectID: projectID}
+		}
+		cacheRemoteSchema(projectID, latestSchema)
+	}
+
+	// Create empty schema if none exists
+	remoteMissing := latestSchema == ""
+	if remoteMissing {
+		latestSchema = fmt.Sprintf(`{
+			"project_id": "%s",
+			"version": 0,
+			"tables": {}
+		}`, projectID)
 	}
 
 	// Parse latest schema
@@ -1253,33 +2529,51 @@ func checkStatusCmd() tea.Msg {
 		return statusMsg{text: strings.Join(messages, "\n"), projectID: projectID}
 	}
 
-	// Get and compare versions
-	currentVersion, ok := schemaData["version"].(float64)
-	if !ok {
-		messages = append(messages, "No version found in current schema")
+	// Get and compare versions. 0 is a real version, only a missing key is an error
+	currentVersion, err := readSchemaVersion(schemaData)
+	if err != nil {
+		messages = append(messages, fmt.Sprintf("Invalid current schema: %v", err))
 		return statusMsg{text: strings.Join(messages, "\n"), projectID: projectID}
 	}
 
-	latestVersion, ok := latestSchemaData["version"].(float64)
-	if !ok {
-		messages = append(messages, "No version found in latest schema")
+	latestVersion, err := readSchemaVersion(latestSchemaData)
+	if err != nil {
+		messages = append(messages, fmt.Sprintf("Invalid latest schema: %v", err))
 		return statusMsg{text: strings.Join(messages, "\n"), projectID: projectID}
 	}
 
-	messages = append(messages, fmt.Sprintf("Remote schema version: %.0f", latestVersion))
+	if remoteMissing {
+		messages = append(messages, "No schema has been published for this project yet (remote version 0)")
+	} else {
+		messages = append(messages, fmt.Sprintf("Remote schema version: %.0f", latestVersion))
+	}
 
 	// Handle version differences
 	if currentVersion < latestVersion {
 		messages = append(messages,
-			fmt.Sprintf("Schema is out of date! Current: %.0f, Latest: %.0f", currentVersion, latestVersion),
-			"Please run 'basic pull' to update your local schema.")
-		return statusMsg{text: strings.Join(messages, "\n"), status: "behind", projectID: projectID}
+			fmt.Sprintf("Schema is out of date! Current: %.0f, Latest: %.0f", currentVersion, latestVersion))
+		localEdits := hasLocalSchemaEdits(projectID, schemaData, currentVersion, noFetch)
+		if localEdits {
+			messages = append(messages,
+				"",
+				fmt.Sprintf("Warning: your local schema has changes that aren't in version %.0f.", currentVersion),
+				"Pulling will overwrite them. Run 'basic pull --check' to see the differences first,",
+				"then re-apply your changes on top of the latest version.")
+		} else {
+			messages = append(messages, "Please run 'basic pull' to update your local schema.")
+		}
+		return statusMsg{text: strings.Join(messages, "\n"), status: "behind", projectID: projectID, localVersion: currentVersion, remoteVersion: latestVersion, localEdits: localEdits}
 	}
 
 	if currentVersion > latestVersion {
 		messages = append(messages,
 			fmt.Sprintf("Changes found: Local schema version %.0f is ahead of remote version %.0f", currentVersion, latestVersion))
 
+		if noFetch {
+			messages = append(messages, "Run 'basic status' without --no-fetch to validate your changes before pushing.")
+			return statusMsg{text: strings.Join(messages, "\n"), schema: schema, projectID: projectID, localVersion: currentVersion, remoteVersion: latestVersion}
+		}
+
 		valid, err := validateSchema(schema)
 		if err != nil {
 			messages = append(messages, fmt.Sprintf("Error validating schema: %v", err))
@@ -1287,22 +2581,25 @@ func checkStatusCmd() tea.Msg {
 		}
 
 		if valid.Valid != nil && !*valid.Valid {
-			messages = append(messages, "Errors found in schema! Please fix:")
-			for _, err := range valid.Errors {
-				messages = append(messages, fmt.Sprintf(" - %s", err.Message))
-			}
-			return statusMsg{text: strings.Join(messages, "\n"), status: "invalid", schema: schema, projectID: projectID}
+			result := newValidationResult(valid)
+			messages = append(messages, formatValidationResult(result)...)
+			return statusMsg{text: strings.Join(messages, "\n"), status: "invalid", errors: len(result.Errors), schema: schema, projectID: projectID, localVersion: currentVersion, remoteVersion: latestVersion}
 		}
 
 		messages = append(messages,
 			"Schema changes are valid!",
 			"Please run 'basic push' if you are ready to publish your changes.")
-		return statusMsg{text: strings.Join(messages, "\n"), status: "valid", schema: schema, projectID: projectID}
+		return statusMsg{text: strings.Join(messages, "\n"), status: "valid", schema: schema, projectID: projectID, localVersion: currentVersion, remoteVersion: latestVersion}
 	}
 
 	if currentVersion == latestVersion {
 
-		valid, err := checkSchemaConflict(schema)
+		var valid bool
+		if noFetch {
+			valid = len(filterIgnoredChanges(diffSchemas(latestSchemaData, schemaData), schemaIgnorePaths())) == 0
+		} else {
+			valid, err = checkSchemaConflict(schema)
+		}
 		if err != nil {
 			messages = append(messages, fmt.Sprintf("Error checking schema conflict: %v", err))
 			return statusMsg{text: strings.Join(messages, "\n"), projectID: projectID}
@@ -1310,38 +2607,133 @@ func checkStatusCmd() tea.Msg {
 
 		if valid {
 			messages = append(messages, "Schema is up to date!")
-			return statusMsg{text: strings.Join(messages, "\n"), status: "current", schema: schema, projectID: projectID}
+			return statusMsg{text: strings.Join(messages, "\n"), status: "current", schema: schema, projectID: projectID, localVersion: currentVersion, remoteVersion: latestVersion}
 		} else {
 			messages = append(messages, "")
 			messages = append(messages, "Schema conflicts found! Your local schema is different from the remote schema.")
+			messages = append(messages, "- Run 'basic diff' to see what changed.")
 			messages = append(messages, "- Please run 'basic pull' to override local changes with remote schema.")
 			messages = append(messages, "- or increment the version number in your local schema.")
-			return statusMsg{text: strings.Join(messages, "\n"), status: "conflict", schema: schema, projectID: projectID}
+			if configFile, err := findConfigFile(); err == nil && hasUncommittedChanges(configFile) {
+				messages = append(messages, "", fmt.Sprintf("Note: your %s has uncommitted changes, so the differences likely come from your local edits.", configFile))
+			}
+			return statusMsg{text: strings.Join(messages, "\n"), status: "conflict", schema: schema, projectID: projectID, localVersion: currentVersion, remoteVersion: latestVersion}
 		}
 	}
 
 	return statusErrorMsg{err: fmt.Errorf("unknown schema status")}
 }
 
-func checkSchemaConflict(schema string) (bool, error) {
-	var schemaObj map[string]interface{}
-	if err := json.Unmarshal([]byte(schema), &schemaObj); err != nil {
-		return false, fmt.Errorf("error parsing schema JSON: %v", err)
+// remoteOnlyStatus is the status of a --project that has no local config
+// here yet: just its remote version
+func remoteOnlyStatus(projectID string, noFetch bool) tea.Msg {
+	messages := []string{fmt.Sprintf("Project: %s", describeProject(projectID, !noFetch))}
+
+	var remote string
+	if noFetch {
+		entry, ok := loadRemoteSchemaCache()[projectID]
+		if !ok {
+			messages = append(messages, "No cached remote schema for this project yet. Run 'basic status' once while online.")
+			return statusMsg{text: strings.Join(messages, "\n"), projectID: projectID}
+		}
+		remote = entry.Schema
+	} else {
+		var err error
+		remote, err = getProjectSchema(projectID)
+		if err != nil {
+			messages = append(messages, fmt.Sprintf("Error fetching latest schema: %v", err))
+			return statusMsg{text: strings.Join(messages, "\n"), projectID: projectID}
+		}
+		cacheRemoteSchema(projectID, remote)
 	}
 
-	reqBody := struct {
-		Schema map[string]interface{} `json:"schema"`
-	}{
-		Schema: schemaObj,
+	var remoteVersion float64
+	if remoteData, err := parseSchemaJSON(remote); remote != "" && err == nil {
+		remoteVersion, _ = readSchemaVersion(remoteData)
+		messages = append(messages, fmt.Sprintf("Remote schema version: %.0f", remoteVersion))
+	} else {
+		messages = append(messages, "No schema has been published for this project yet (remote version 0)")
 	}
+	messages = append(messages, "No local schema for this project in this directory.",
+		fmt.Sprintf("Run 'basic pull --project %s' to create a config with its schema.", projectID))
+	return statusMsg{text: strings.Join(messages, "\n"), projectID: projectID, remoteVersion: remoteVersion}
+}
 
-	jsonBody, err := json.Marshal(reqBody)
+// hasLocalSchemaEdits reports whether the local schema differs from the
+// published schema of the version it claims to be. If that version can't be
+// fetched, uncommitted git changes to the config are taken as a sign of edits.
+func hasLocalSchemaEdits(projectID string, localSchema map[string]interface{}, localVersion float64, noFetch bool) bool {
+	if !noFetch {
+		if versions, err := getSchemaHistory(projectID); err == nil {
+			for _, v := range versions {
+				if float64(v.Version) == localVersion && v.Schema != nil {
+					changes := filterIgnoredChanges(diffSchemas(v.Schema, localSchema), schemaIgnorePaths())
+					return len(changes) > 0
+				}
+			}
+		}
+	}
+
+	configFile, err := findConfigFile()
+	return err == nil && hasUncommittedChanges(configFile)
+}
+
+// explainStatus spells out the exact next steps for a schema status
+func explainStatus(msg statusMsg) []string {
+	configFile, err := findConfigFile()
+	if err != nil {
+		configFile = configFileNames[0]
+	}
+
+	lines := []string{"", "Next steps:"}
+	switch msg.status {
+	case "current":
+		lines = append(lines, "  Nothing to do - your local schema matches the remote schema.")
+	case "behind":
+		lines = append(lines,
+			fmt.Sprintf("  Remote has published version %.0f since your local version %.0f.", msg.remoteVersion, msg.localVersion),
+			"  To take the remote schema (this overwrites local changes):",
+			"    basic pull",
+			"  To keep local changes, copy them somewhere first, pull, then re-apply them and",
+			fmt.Sprintf("  set \"version\": %.0f in %s before running 'basic push'.", msg.remoteVersion+1, configFile))
+	case "conflict":
+		lines = append(lines,
+			fmt.Sprintf("  Your local schema differs from remote version %.0f but uses the same version number.", msg.remoteVersion),
+			"  To discard local changes and take the remote schema:",
+			"    basic pull",
+			"  To publish your local changes instead:",
+			fmt.Sprintf("    set \"version\": %.0f in %s", msg.remoteVersion+1, configFile),
+			"    basic push")
+	case "valid":
+		lines = append(lines,
+			fmt.Sprintf("  Publish local version %.0f (remote is at version %.0f):", msg.localVersion, msg.remoteVersion),
+			"    basic push")
+	case "invalid":
+		lines = append(lines,
+			fmt.Sprintf("  Fix the errors above in %s, then check again and publish:", configFile),
+			"    basic status",
+			"    basic push")
+	default:
+		if msg.projectID == "" {
+			lines = append(lines,
+				"  Create a config for a new or existing project:",
+				"    basic init")
+		} else {
+			lines = append(lines, "  Check your connection and config, then run 'basic status' again.")
+		}
+	}
+	return lines
+}
+
+func checkSchemaConflict(schema string) (bool, error) {
+	jsonBody, err := schemaRequestBody(schema)
 	if err != nil {
-		return false, fmt.Errorf("error marshaling request body: %v", err)
+		return false, err
 	}
 
-	client := &http.Client{}
-	req, err := http.NewRequest("POST", "https://api.basic.tech/schema/compareSchema", bytes.NewBuffer(jsonBody))
+	client := apiClient()
+	url := "https://api.basic.tech/schema/compareSchema"
+	req, err := http.NewRequest("POST", url, bytes.NewBuffer(jsonBody))
 	if err != nil {
 		return false, fmt.Errorf("error creating request: %v", err)
 	}
@@ -1350,7 +2742,7 @@ func checkSchemaConflict(schema string) (bool, error) {
 
 	resp, err := client.Do(req)
 	if err != nil {
-		return false, fmt.Errorf("error checking schema conflict: %v", err)
+		return false, apiRequestError("error checking schema conflict", url, err)
 	}
 	defer resp.Body.Close()
 
@@ -1375,10 +2767,50 @@ func checkSchemaConflict(schema string) (bool, error) {
 	return response.Valid, nil
 }
 
+// release channels: stable only sees full releases, beta also sees pre-releases
+const (
+	stableChannel = "stable"
+	betaChannel   = "beta"
+)
+
+// updateChannel returns --channel, then the channel saved by
+// 'basic update --channel', then stable
+func updateChannel() string {
+	if channel := flagValue("--channel"); channel != "" {
+		return channel
+	}
+	if settings, err := loadSettings(); err == nil && settings.UpdateChannel != "" {
+		return settings.UpdateChannel
+	}
+	return stableChannel
+}
+
+// saveUpdateChannel remembers --channel for future version checks and updates
+func saveUpdateChannel(channel string) error {
+	if channel != stableChannel && channel != betaChannel {
+		return fmt.Errorf("unknown channel %q. Use --channel stable or --channel beta", channel)
+	}
+	settings, err := loadSettings()
+	if err != nil {
+		return err
+	}
+	settings.UpdateChannel = channel
+	if channel == stableChannel {
+		settings.UpdateChannel = ""
+	}
+	return saveSettings(settings)
+}
+
+// checkLatestRelease returns the newest version on the update channel
 func checkLatestRelease() (string, error) {
-	resp, err := http.Get("https://api.github.com/repos/basicdb/basic-cli/releases/latest")
+	if updateChannel() == betaChannel {
+		return checkLatestPrerelease()
+	}
+
+	url := "https://api.github.com/repos/basicdb/basic-cli/releases/latest"
+	resp, err := apiClient().Get(url)
 	if err != nil {
-		return "", fmt.Errorf("error checking for updates: %w", err)
+		return "", apiRequestError("error checking for updates", url, err)
 	}
 	defer resp.Body.Close()
 
@@ -1398,28 +2830,186 @@ func checkLatestRelease() (string, error) {
 	return version, nil
 }
 
+// checkLatestPrerelease returns the newest published release, pre-releases
+// included. GitHub lists releases newest first.
+func checkLatestPrerelease() (string, error) {
+	url := "https://api.github.com/repos/basicdb/basic-cli/releases?per_page=20"
+	resp, err := apiClient().Get(url)
+	if err != nil {
+		return "", apiRequestError("error checking for updates", url, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("received non-200 response checking releases: %d", resp.StatusCode)
+	}
+
+	var releases []struct {
+		TagName string `json:"tag_name"`
+		Draft   bool   `json:"draft"`
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(&releases); err != nil {
+		return "", fmt.Errorf("error parsing release info: %w", err)
+	}
+
+	for _, release := range releases {
+		if !release.Draft {
+			return strings.TrimPrefix(release.TagName, "v"), nil
+		}
+	}
+	return "", fmt.Errorf("no releases found")
+}
+
+// installMethod guesses how the CLI was installed from the path of the
+// running binary: "bun", "npm" or "unknown"
+func installMethod() string {
+	executable, err := os.Executable()
+	if err != nil {
+		return "unknown"
+	}
+	if resolved, err := filepath.EvalSymlinks(executable); err == nil {
+		executable = resolved
+	}
+
+	path := filepath.ToSlash(executable)
+	switch {
+	case strings.Contains(path, "/.bun/"):
+		return "bun"
+	case strings.Contains(path, "/node_modules/"):
+		return "npm"
+	}
+	return "unknown"
+}
+
+// updateCommand is the command that updates the CLI for an install method.
+// npm is the documented install, so it's also the fallback. The beta channel
+// installs the exact pre-release version, as 'latest' never points at one.
+func updateCommand(method string, latestVersion string) []string {
+	if updateChannel() == betaChannel && latestVersion != "" {
+		if method == "bun" {
+			return []string{"bun", "add", "-g", "@basictech/cli@" + latestVersion}
+		}
+		return []string{"npm", "install", "-g", "@basictech/cli@" + latestVersion}
+	}
+	if method == "bun" {
+		return []string{"bun", "add", "-g", "@basictech/cli@latest"}
+	}
+	return []string{"npm", "update", "-g", "@basictech/cli"}
+}
+
+type versionInfo struct {
+	Version         string `json:"version"`
+	LatestVersion   string `json:"latest_version,omitempty"`
+	UpdateAvailable bool   `json:"update_available"`
+	InstallMethod   string `json:"install_method"`
+	UpdateCommand   string `json:"update_command"`
+	Channel         string `json:"channel"`
+	OS              string `json:"os"`
+	Arch            string `json:"arch"`
+	Error           string `json:"error,omitempty"`
+}
+
+func getVersionInfo() versionInfo {
+	method := installMethod()
+	info := versionInfo{
+		Version:       version,
+		InstallMethod: method,
+		Channel:       updateChannel(),
+		OS:            runtime.GOOS,
+		Arch:          runtime.GOARCH,
+	}
+	if latestVersion, err := checkLatestRelease(); err != nil {
+		info.Error = err.Error()
+	} else {
+		info.LatestVersion = latestVersion
+		info.UpdateAvailable = latestVersion != version
+	}
+	info.UpdateCommand = strings.Join(updateCommand(method, info.LatestVersion), " ")
+	return info
+}
+
+func printVersionJSON() {
+	out, _ := json.MarshalIndent(getVersionInfo(), "", "  ")
+	fmt.Println(string(out))
+}
+
+const newIssueURL = "https://github.com/basicdb/basic-cli/issues/new"
+
+// performFeedback opens a new GitHub issue prefilled with the environment
+// details we always end up asking for
+func performFeedback() tea.Msg {
+	info, _ := json.MarshalIndent(getVersionInfo(), "", "  ")
+	body := fmt.Sprintf("**What happened?**\n\n\n**What did you expect to happen?**\n\n\n"+
+		"**Steps to reproduce**\n\n1. \n\n**Environment** (`basic version --json`)\n\n```json\n%s\n```\n", info)
+	issueURL := newIssueURL + "?body=" + url.QueryEscape(body)
+
+	if err := openBrowser(issueURL); err != nil {
+		fmt.Printf("Error opening browser: %v\n", err)
+		fmt.Printf("Please open this URL to file an issue: %s\n", issueURL)
+		return tea.Quit()
+	}
+	fmt.Println("Opened a new issue in your browser, thanks for the feedback!")
+	return tea.Quit()
+}
+
 // ------- list projects table ----------- //
 
-func displayProjects(projects []project) (tea.Model, tea.Cmd) {
-	columns := []table.Column{
-		{Title: "ID", Width: 36},
-		// {Title: "Owner", Width: 20},
-		{Title: "Name", Width: 30},
-		{Title: "Website", Width: 30},
+func displayProjects(projects []project, syncStatuses map[string]string, cachedAt time.Time, userID string) (tea.Model, tea.Cmd) {
+	// only render the first --limit projects so huge lists stay fast
+	limit := defaultProjectsLimit
+	if l, err := strconv.Atoi(flagValue("--limit")); err == nil && l > 0 {
+		limit = l
+	}
+	total := len(projects)
+
+	owned := 0
+	for _, p := range projects {
+		if userID != "" && p.Owner == userID {
+			owned++
+		}
+	}
+
+	// the status column only shows up when a project has a local config
+	statusWidth := 0
+	statusCells := map[string]string{}
+	for _, p := range projects {
+		if status, ok := syncStatuses[p.ID]; ok {
+			statusCells[p.ID] = renderSyncStatus(status)
+			// the table measures cells including color codes, so size by bytes
+			statusWidth = max(statusWidth, len(statusCells[p.ID]), len("Status"))
+		}
 	}
 
 	rows := []table.Row{}
 	for _, p := range projects {
-		rows = append(rows, table.Row{p.ID, p.Name, p.Website})
+		row := table.Row{p.ID, p.Name, p.Website}
+		if statusWidth > 0 {
+			row = append(row, statusCells[p.ID])
+		}
+		rows = append(rows, row)
+	}
+	shown := rows
+	if len(shown) > limit {
+		shown = shown[:limit]
 	}
 
+	columns := projectColumns(maxWidth, statusWidth)
 	t := table.New(
 		table.WithColumns(columns),
-		table.WithRows(rows),
+		table.WithRows(shown),
 		table.WithFocused(true),
-		table.WithHeight(len(projects)+1),
+		table.WithHeight(len(shown)+1),
 	)
+	t.SetStyles(tableStyles())
 
+	// the program is already running, so ask for the terminal size again
+	return projectTableModel{table: t, total: total, owned: owned, ownerKnown: userID != "", statusWidth: statusWidth, cachedAt: cachedAt,
+		allRows: rows, limit: limit}, tea.WindowSize()
+}
+
+// tableStyles is the look shared by the projects and data tables
+func tableStyles() table.Styles {
 	s := table.DefaultStyles()
 	s.Header = s.Header.
 		BorderStyle(lipgloss.NormalBorder()).
@@ -1430,15 +3020,85 @@ func displayProjects(projects []project) (tea.Model, tea.Cmd) {
 		Foreground(lipgloss.Color("229")).
 		Background(lipgloss.Color("57")).
 		Bold(false)
-	t.SetStyles(s)
+	return s
+}
+
+func renderSyncStatus(status string) string {
+	switch status {
+	case "synced":
+		return lipgloss.NewStyle().Foreground(lipgloss.Color("2")).Render("= synced")
+	case "behind":
+		return lipgloss.NewStyle().Foreground(lipgloss.Color("3")).Render("↓ behind")
+	case "ahead":
+		return lipgloss.NewStyle().Foreground(lipgloss.Color("6")).Render("↑ ahead")
+	default:
+		return ""
+	}
+}
 
-	return projectTableModel{table: t}, nil
+// projectColumns fits the table columns into width, shrinking the ID to a
+// short prefix on narrow terminals. Cells are truncated with an ellipsis by
+// the table, and the full values are still used when copying/opening.
+// statusWidth is 0 when there's no status column.
+func projectColumns(width int, statusWidth int) []table.Column {
+	const (
+		cellPadding  = 2 // each cell is padded by 1 on both sides
+		fullIDWidth  = 36
+		shortIDWidth = 8
+		minWidth     = 10
+		maxNameWidth = 30
+		maxSiteWidth = 40
+	)
+
+	available := width - 3*cellPadding
+	if statusWidth > 0 {
+		available -= statusWidth + cellPadding
+	}
+
+	idWidth := fullIDWidth
+	if available-idWidth < 2*minWidth+minWidth {
+		idWidth = shortIDWidth
+	}
+
+	rest := available - idWidth
+	nameWidth := min(max(rest/2, minWidth), maxNameWidth)
+	siteWidth := min(max(rest-nameWidth, minWidth), maxSiteWidth)
+
+	columns := []table.Column{
+		{Title: "ID", Width: idWidth},
+		// {Title: "Owner", Width: 20},
+		{Title: "Name", Width: nameWidth},
+		{Title: "Website", Width: siteWidth},
+	}
+	if statusWidth > 0 {
+		columns = append(columns, table.Column{Title: "Status", Width: statusWidth})
+	}
+	return columns
 }
 
+const defaultProjectsLimit = 50
+
 type projectTableModel struct {
-	table             table.Model
+	table table.Model
+	total int
+	owned int
+	// false when we couldn't tell which projects are ours
+	ownerKnown        bool
+	statusWidth       int
 	notification      string
 	notificationTimer *time.Timer
+	// set when the projects come from the offline cache
+	cachedAt time.Time
+	// terminal height, to resize the table when a project is added
+	height int
+	// the project name prompt shown by 'n'
+	createForm *huh.Form
+	// every project, of which the table shows up to limit that match filter
+	allRows []table.Row
+	limit   int
+	filter  string
+	// set while typing the filter after '/'
+	filtering bool
 }
 
 func (m projectTableModel) Init() tea.Cmd {
@@ -1449,11 +3109,64 @@ type clearNotificationMsg struct{}
 
 func (m projectTableModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	var cmd tea.Cmd
+	if m.createForm != nil {
+		return m.updateCreateForm(msg)
+	}
+
 	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.height = msg.Height
+		m.table.SetColumns(projectColumns(msg.Width, m.statusWidth))
+		m.resizeTable()
+	case newProjectMsg:
+		if msg.err != nil {
+			return m.notify(fmt.Sprintf("Error creating project: %v", msg.err))
+		}
+		row := table.Row{msg.projectID, msg.projectName, ""}
+		if m.statusWidth > 0 {
+			row = append(row, "")
+		}
+		m.allRows = append([]table.Row{row}, m.allRows...)
+		m.filter = ""
+		m.applyFilter()
+		m.table.SetCursor(0)
+		m.total++
+		m.owned++
+		return m.notify(fmt.Sprintf("%s: project created!", msg.projectName))
 	case tea.KeyMsg:
+		if m.filtering {
+			return m.updateFilter(msg)
+		}
 		switch msg.String() {
-		case "ctrl+c", "esc":
+		case "ctrl+c":
 			return m, tea.Quit
+		case "esc":
+			if m.filter != "" {
+				m.filter = ""
+				m.applyFilter()
+				return m, nil
+			}
+			return m, tea.Quit
+		case "/":
+			m.filtering = true
+			m.notification = ""
+			return m, nil
+		case "n":
+			m.notification = ""
+			m.createForm = huh.NewForm(
+				huh.NewGroup(
+					huh.NewInput().
+						Key("name").
+						Title("New project name").
+						Validate(func(v string) error {
+							if strings.TrimSpace(v) == "" {
+								return fmt.Errorf("project name is required")
+							}
+							return nil
+						}),
+				),
+			).WithShowHelp(false)
+			return m, m.createForm.Init()
 		case "up", "down":
 			m.notification = ""
 		case "c":
@@ -1462,20 +3175,23 @@ func (m projectTableModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				projectID := selectedRow[0]
 				projectName := selectedRow[1]
 				clipboard.WriteAll(projectID)
-				m.notification = fmt.Sprintf("%s: project_id copied to clipboard!", projectName)
+				return m.notify(fmt.Sprintf("%s: project_id copied to clipboard!", projectName))
+			}
 
-				if m.notificationTimer != nil {
-					m.notificationTimer.Stop()
-				}
+		case "y":
+			selectedRow := m.table.SelectedRow()
+			if len(selectedRow) > 0 {
+				projectID := selectedRow[0]
+				projectName := selectedRow[1]
+				clipboard.WriteAll(fmt.Sprintf("%s — %s", projectName, projectID))
+				return m.notify(fmt.Sprintf("%s: name and project_id copied to clipboard!", projectName))
+			}
 
-				m.notificationTimer = time.NewTimer(5 * time.Second)
-				return m, tea.Batch(
-					cmd,
-					func() tea.Msg {
-						<-m.notificationTimer.C
-						return clearNotificationMsg{}
-					},
-				)
+		case "m":
+			rows := m.table.Rows()
+			if len(rows) > 0 {
+				clipboard.WriteAll(projectsMarkdownTable(rows))
+				return m.notify(fmt.Sprintf("%s copied to clipboard as a Markdown table!", countNoun(len(rows), "project")))
 			}
 
 		case "o":
@@ -1492,23 +3208,190 @@ func (m projectTableModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, cmd
 }
 
-func (m projectTableModel) View() string {
-	notification := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("57")).
-		Render(m.notification)
-
-	help := "\n \n" +
-		notification +
-		"\n'c' to copy project ID" +
-		" • 'o' to open project in browser" +
-		"\n↑/↓ to navigate" +
-		" • esc to quit"
+// updateFilter edits the filter typed after '/', narrowing the table down
+// as it changes. enter keeps the filter, esc clears it.
+func (m projectTableModel) updateFilter(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyCtrlC:
+		return m, tea.Quit
+	case tea.KeyEsc:
+		m.filter = ""
+		m.filtering = false
+	case tea.KeyEnter:
+		m.filtering = false
+		return m, nil
+	case tea.KeyBackspace:
+		if runes := []rune(m.filter); len(runes) > 0 {
+			m.filter = string(runes[:len(runes)-1])
+		}
+	case tea.KeyRunes, tea.KeySpace:
+		m.filter += string(msg.Runes)
+	default:
+		return m, nil
+	}
+	m.applyFilter()
+	return m, nil
+}
 
-	help = lipgloss.NewStyle().
-		Foreground(lipgloss.Color("240")).
-		Render(help)
+// applyFilter shows the first limit projects whose name or ID contains the filter
+func (m *projectTableModel) applyFilter() {
+	query := strings.ToLower(m.filter)
+	rows := []table.Row{}
+	for _, row := range m.allRows {
+		if len(rows) == m.limit {
+			break
+		}
+		if strings.Contains(strings.ToLower(row[0]), query) || strings.Contains(strings.ToLower(row[1]), query) {
+			rows = append(rows, row)
+		}
+	}
+	m.table.SetRows(rows)
+	m.table.SetCursor(0)
+	m.resizeTable()
+}
+
+// matchingProjects counts the projects the filter matches, beyond the limit
+func (m projectTableModel) matchingProjects() int {
+	query := strings.ToLower(m.filter)
+	count := 0
+	for _, row := range m.allRows {
+		if strings.Contains(strings.ToLower(row[0]), query) || strings.Contains(strings.ToLower(row[1]), query) {
+			count++
+		}
+	}
+	return count
+}
+
+// updateCreateForm runs the new project prompt, creating the project once a
+// name is entered. esc goes back to the table.
+func (m projectTableModel) updateCreateForm(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		switch msg.String() {
+		case "ctrl+c":
+			return m, tea.Quit
+		case "esc":
+			m.createForm = nil
+			return m, nil
+		}
+	}
 
-	return "\n" + m.table.View() + "\n\n" + help
+	form, cmd := m.createForm.Update(msg)
+	if f, ok := form.(*huh.Form); ok {
+		m.createForm = f
+		switch f.State {
+		case huh.StateCompleted:
+			name := strings.TrimSpace(f.GetString("name"))
+			m.createForm = nil
+			m.notification = fmt.Sprintf("Creating %s...", name)
+			return m, func() tea.Msg {
+				return createNewProjectMsg(name, generateSlugFromName(name))
+			}
+		case huh.StateAborted:
+			m.createForm = nil
+			return m, nil
+		}
+	}
+	return m, cmd
+}
+
+// projectsMarkdownTable renders the table's rows as a Markdown table. The
+// status column is left out since it's only colored symbols.
+func projectsMarkdownTable(rows []table.Row) string {
+	escape := strings.NewReplacer("|", "\\|", "\n", " ")
+
+	var b strings.Builder
+	b.WriteString("| ID | Name | Website |\n")
+	b.WriteString("| --- | --- | --- |\n")
+	for _, row := range rows {
+		fmt.Fprintf(&b, "| %s | %s | %s |\n", escape.Replace(row[0]), escape.Replace(row[1]), escape.Replace(row[2]))
+	}
+	return b.String()
+}
+
+// resizeTable fits the table to its rows, leaving room for the header and
+// help footer
+func (m *projectTableModel) resizeTable() {
+	height := len(m.table.Rows()) + 1
+	if m.height > 0 {
+		height = min(height, m.height-10)
+	}
+	m.table.SetHeight(max(height, 3))
+}
+
+// notify shows text in the footer for a few seconds
+func (m projectTableModel) notify(text string) (tea.Model, tea.Cmd) {
+	m.notification = text
+
+	if m.notificationTimer != nil {
+		m.notificationTimer.Stop()
+	}
+
+	m.notificationTimer = time.NewTimer(5 * time.Second)
+	timer := m.notificationTimer
+	return m, func() tea.Msg {
+		<-timer.C
+		return clearNotificationMsg{}
+	}
+}
+
+func (m projectTableModel) View() string {
+	if m.createForm != nil {
+		return "\n" + m.createForm.View() + "\n\n" + lipgloss.NewStyle().
+			Foreground(lipgloss.Color("240")).
+			Render("enter to create • esc to go back")
+	}
+
+	notification := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("57")).
+		Render(m.notification)
+
+	var count string
+	switch shown := len(m.table.Rows()); {
+	case m.filtering || m.filter != "":
+		cursor := ""
+		if m.filtering {
+			cursor = "_"
+		}
+		count = fmt.Sprintf("/%s%s • %d of %d match • esc to clear\n", m.filter, cursor, m.matchingProjects(), m.total)
+	case shown < m.total:
+		count = fmt.Sprintf("showing %d of %d — use / to filter\n", shown, m.total)
+	}
+
+	help := "\n \n" +
+		notification +
+		"\n" + count +
+		"'c' to copy project ID" +
+		" • 'y' to copy name and ID" +
+		" • 'o' to open project in browser" +
+		" • 'n' to create a project" +
+		" • 'm' to copy as Markdown" +
+		" • '/' to filter" +
+		"\n↑/↓ to navigate" +
+		" • esc to quit"
+
+	help = lipgloss.NewStyle().
+		Foreground(lipgloss.Color("240")).
+		Render(help)
+
+	var banner string
+	if !m.cachedAt.IsZero() {
+		banner = "\n" + lipgloss.NewStyle().
+			Foreground(lipgloss.Color("3")).
+			Render(cachedProjectsBanner(m.cachedAt)) + "\n"
+	}
+
+	summary := lipgloss.NewStyle().Bold(true).Render(m.summary())
+
+	return banner + "\n" + summary + "\n\n" + m.table.View() + "\n\n" + help
+}
+
+// summary is the line above the table, e.g. "12 projects (8 owned, 4 shared)"
+func (m projectTableModel) summary() string {
+	summary := countNoun(m.total, "project")
+	if m.ownerKnown {
+		summary += fmt.Sprintf(" (%d owned, %d shared)", m.owned, m.total-m.owned)
+	}
+	return summary
 }
 
 // ----------------------------- //
@@ -1522,19 +3405,90 @@ func (m projectTableModel) View() string {
 type projectsMsg struct {
 	projects []project
 	err      error
+
+	// project ID -> "synced", "behind" or "ahead" for projects with a local config
+	syncStatuses map[string]string
+
+	// set when projects were loaded from the offline cache
+	cachedAt time.Time
+
+	// the logged in user, to tell owned projects from shared ones
+	userID string
 }
 
 type project struct {
-	ID      string
-	Owner   string
-	Name    string
-	Website string
+	ID        string
+	Owner     string
+	Name      string
+	Website   string
+	CreatedAt string `json:"created_at"`
 }
 
+// getProjectSchema returns the project's schema, or "" if it has none. When the
+// project has several schema records, --schema-index picks one (default 0).
 func getProjectSchema(projectID string) (string, error) {
-	resp, err := http.Get("https://api.basic.tech/project/" + projectID + "/schema")
+	schemas, err := getProjectSchemas(projectID)
+	if err != nil {
+		return "", err
+	}
+	return selectProjectSchema(schemas)
+}
+
+// selectProjectSchema picks the --schema-index record out of schemas
+func selectProjectSchema(schemas []string) (string, error) {
+	index, err := schemaIndex()
+	if err != nil {
+		return "", err
+	}
+	if len(schemas) == 0 {
+		return "", nil
+	}
+	if index >= len(schemas) {
+		return "", fmt.Errorf("--schema-index %d is out of range, project has %d schema records", index, len(schemas))
+	}
+	return schemas[index], nil
+}
+
+// getProjectSchemas returns every schema record of the project, as formatted
+// JSON ("" for an empty record)
+func getProjectSchemas(projectID string) ([]string, error) {
+	fetchedSchemasMu.Lock()
+	schemas, ok := fetchedSchemas[projectID]
+	fetchedSchemasMu.Unlock()
+	if ok {
+		return schemas, nil
+	}
+
+	// not locked while fetching, so several projects can be fetched at once
+	schemas, err := fetchProjectSchemas(projectID)
 	if err != nil {
-		return "", fmt.Errorf("error fetching project schema: %v", err)
+		return nil, err
+	}
+	fetchedSchemasMu.Lock()
+	fetchedSchemas[projectID] = schemas
+	fetchedSchemasMu.Unlock()
+	return schemas, nil
+}
+
+// fetchedSchemas remembers each project's schemas for the rest of the
+// command, as status, push and pull each look at the remote schema more than
+// once. Call forgetFetchedSchemas when the remote schema may have changed.
+var (
+	fetchedSchemas   = map[string][]string{}
+	fetchedSchemasMu sync.Mutex
+)
+
+func forgetFetchedSchemas() {
+	fetchedSchemasMu.Lock()
+	defer fetchedSchemasMu.Unlock()
+	fetchedSchemas = map[string][]string{}
+}
+
+func fetchProjectSchemas(projectID string) ([]string, error) {
+	url := "https://api.basic.tech/project/" + projectID + "/schema"
+	resp, err := apiClient().Get(url)
+	if err != nil {
+		return nil, apiRequestError("error fetching project schema", url, err)
 	}
 	defer resp.Body.Close()
 
@@ -1545,23 +3499,55 @@ func getProjectSchema(projectID string) (string, error) {
 	}
 
 	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
-		return "", fmt.Errorf("error parsing JSON response: %v", err)
+		return nil, fmt.Errorf("error parsing JSON response: %v", err)
 	}
 
-	if len(response.Data) == 0 {
-		return "", nil
+	schemas := []string{}
+	for _, d := range response.Data {
+		prettyJSON, err := json.MarshalIndent(d.Schema, "\t", "\t")
+		if err != nil {
+			return nil, fmt.Errorf("error formatting schema JSON: %v", err)
+		}
+		if string(prettyJSON) == "null" {
+			prettyJSON = nil
+		}
+		schemas = append(schemas, string(prettyJSON))
 	}
 
-	prettyJSON, err := json.MarshalIndent(response.Data[0].Schema, "\t", "\t")
-	if err != nil {
-		return "", fmt.Errorf("error formatting schema JSON: %v", err)
+	return schemas, nil
+}
+
+// schemaIndex returns the --schema-index flag, 0 when it isn't set
+func schemaIndex() (int, error) {
+	value := flagValue("--schema-index")
+	if value == "" {
+		return 0, nil
+	}
+	index, err := strconv.Atoi(value)
+	if err != nil || index < 0 {
+		return 0, fmt.Errorf("--schema-index must be a number of 0 or more")
 	}
+	return index, nil
+}
 
-	if string(prettyJSON) == "null" {
-		return "", nil
+// checkProjectAccess makes sure projectID is one of the user's projects, so a
+// mistyped or copied project_id gets a clear error instead of a failed push
+func checkProjectAccess(token *oauth2.Token, projectID string) error {
+	projects, err := getProjects(token)
+	if err != nil {
+		return err
+	}
+	for _, p := range projects {
+		if p.ID == projectID {
+			return nil
+		}
 	}
 
-	return string(prettyJSON), nil
+	configFile, err := findConfigFile()
+	if err != nil {
+		configFile = configFileNames[0]
+	}
+	return fmt.Errorf("project %s not found among your projects — check %s", projectID, configFile)
 }
 
 func pushProjectSchema(schema string) (bool, error) {
@@ -1582,25 +3568,24 @@ func pushProjectSchema(schema string) (bool, error) {
 		return false, fmt.Errorf("not logged in")
 	}
 
-	// Create request body
-	reqBody := struct {
-		Schema map[string]interface{} `json:"schema"`
-	}{
-		Schema: schemaData,
+	if err := checkProjectAccess(token, projectID); err != nil {
+		return false, err
 	}
 
-	jsonBody, err := json.Marshal(reqBody)
+	// Create request body
+	jsonBody, err := schemaRequestBody(schema)
 	if err != nil {
-		return false, fmt.Errorf("error marshaling request body: %v", err)
+		return false, err
 	}
 
 	// Use the oauth2 client with authentication
-	client := oauthConfig.Client(context.Background(), token)
-	resp, err := client.Post("https://api.basic.tech/project/"+projectID+"/schema",
+	client := authClient(token)
+	url := "https://api.basic.tech/project/" + projectID + "/schema"
+	resp, err := client.Post(url,
 		"application/json",
 		bytes.NewBuffer(jsonBody))
 	if err != nil {
-		return false, fmt.Errorf("error making request: %v", err)
+		return false, apiRequestError("error making request", url, err)
 	}
 	defer resp.Body.Close()
 
@@ -1609,21 +3594,26 @@ func pushProjectSchema(schema string) (bool, error) {
 		return false, fmt.Errorf("received non-200 response: %d - %s", resp.StatusCode, string(body))
 	}
 
+	forgetFetchedSchemas()
 	return true, nil
 }
 
-func validateSchema(schema string) (struct {
-	Valid  *bool `json:"valid,omitempty"`
-	Errors []struct {
-		Message string `json:"message"`
-		Path    string `json:"path"`
-		Change  struct {
-			Path string `json:"path"`
-		} `json:"change"`
-	} `json:"errors,omitempty"`
-	Error   *string `json:"error,omitempty"`
-	Message *string `json:"message,omitempty"`
-}, error) {
+type schemaValidationError struct {
+	Message string `json:"message"`
+	Path    string `json:"path"`
+	Change  struct {
+		Path string `json:"path"`
+	} `json:"change"`
+}
+
+type schemaValidation struct {
+	Valid   *bool                   `json:"valid,omitempty"`
+	Errors  []schemaValidationError `json:"errors,omitempty"`
+	Error   *string                 `json:"error,omitempty"`
+	Message *string                 `json:"message,omitempty"`
+}
+
+func validateSchema(schema string) (schemaValidation, error) {
 	// Create request body
 	reqBody := struct {
 		Schema string `json:"schema"`
@@ -1633,35 +3623,14 @@ func validateSchema(schema string) (struct {
 
 	jsonBody, err := json.Marshal(reqBody)
 	if err != nil {
-		return struct {
-			Valid  *bool `json:"valid,omitempty"`
-			Errors []struct {
-				Message string `json:"message"`
-				Path    string `json:"path"`
-				Change  struct {
-					Path string `json:"path"`
-				} `json:"change"`
-			} `json:"errors,omitempty"`
-			Error   *string `json:"error,omitempty"`
-			Message *string `json:"message,omitempty"`
-		}{}, fmt.Errorf("error marshaling request body: %v", err)
+		return schemaValidation{}, fmt.Errorf("error marshaling request body: %v", err)
 	}
 
 	// Make request to validation endpoint
-	resp, err := http.Post("https://api.basic.tech/schema/verifyUpdateSchema", "application/json", bytes.NewBuffer(jsonBody))
-	if err != nil {
-		return struct {
-			Valid  *bool `json:"valid,omitempty"`
-			Errors []struct {
-				Message string `json:"message"`
-				Path    string `json:"path"`
-				Change  struct {
-					Path string `json:"path"`
-				} `json:"change"`
-			} `json:"errors,omitempty"`
-			Error   *string `json:"error,omitempty"`
-			Message *string `json:"message,omitempty"`
-		}{}, fmt.Errorf("error making validation request: %v", err)
+	url := "https://api.basic.tech/schema/verifyUpdateSchema"
+	resp, err := apiClient().Post(url, "application/json", bytes.NewBuffer(jsonBody))
+	if err != nil {
+		return schemaValidation{}, apiRequestError("error making validation request", url, err)
 	}
 	defer resp.Body.Close()
 
@@ -1669,18 +3638,7 @@ func validateSchema(schema string) (struct {
 		// Read error response body first
 		bodyBytes, err := io.ReadAll(resp.Body)
 		if err != nil {
-			return struct {
-				Valid  *bool `json:"valid,omitempty"`
-				Errors []struct {
-					Message string `json:"message"`
-					Path    string `json:"path"`
-					Change  struct {
-						Path string `json:"path"`
-					} `json:"change"`
-				} `json:"errors,omitempty"`
-				Error   *string `json:"error,omitempty"`
-				Message *string `json:"message,omitempty"`
-			}{}, fmt.Errorf("error reading error response: %v", err)
+			return schemaValidation{}, fmt.Errorf("error reading error response: %v", err)
 		}
 
 		// Reset body for later use
@@ -1690,59 +3648,14 @@ func validateSchema(schema string) (struct {
 			Error string `json:"error"`
 		}
 		if err := json.NewDecoder(resp.Body).Decode(&errResp); err != nil {
-			return struct {
-				Valid  *bool `json:"valid,omitempty"`
-				Errors []struct {
-					Message string `json:"message"`
-					Path    string `json:"path"`
-					Change  struct {
-						Path string `json:"path"`
-					} `json:"change"`
-				} `json:"errors,omitempty"`
-				Error   *string `json:"error,omitempty"`
-				Message *string `json:"message,omitempty"`
-			}{}, fmt.Errorf("error decoding error response: %v", err)
-		}
-		return struct {
-			Valid  *bool `json:"valid,omitempty"`
-			Errors []struct {
-				Message string `json:"message"`
-				Path    string `json:"path"`
-				Change  struct {
-					Path string `json:"path"`
-				} `json:"change"`
-			} `json:"errors,omitempty"`
-			Error   *string `json:"error,omitempty"`
-			Message *string `json:"message,omitempty"`
-		}{}, fmt.Errorf("error: %s", errResp.Error)
-	}
-
-	var response struct {
-		Valid  *bool `json:"valid,omitempty"`
-		Errors []struct {
-			Message string `json:"message"`
-			Path    string `json:"path"`
-			Change  struct {
-				Path string `json:"path"`
-			} `json:"change"`
-		} `json:"errors,omitempty"`
-		Error   *string `json:"error,omitempty"`
-		Message *string `json:"message,omitempty"`
+			return schemaValidation{}, fmt.Errorf("error decoding error response: %v", err)
+		}
+		return schemaValidation{}, fmt.Errorf("error: %s", errResp.Error)
 	}
 
+	var response schemaValidation
 	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
-		return struct {
-			Valid  *bool `json:"valid,omitempty"`
-			Errors []struct {
-				Message string `json:"message"`
-				Path    string `json:"path"`
-				Change  struct {
-					Path string `json:"path"`
-				} `json:"change"`
-			} `json:"errors,omitempty"`
-			Error   *string `json:"error,omitempty"`
-			Message *string `json:"message,omitempty"`
-		}{}, fmt.Errorf("error parsing validation response: %v", err)
+		return schemaValidation{}, fmt.Errorf("error parsing validation response: %v", err)
 	}
 
 	return response, nil
@@ -1750,10 +3663,11 @@ func validateSchema(schema string) (struct {
 }
 
 func getProjects(token *oauth2.Token) ([]project, error) {
-	client := oauthConfig.Client(context.Background(), token)
-	resp, err := client.Get("https://api.basic.tech/account/projects")
+	client := authClient(token)
+	url := "https://api.basic.tech/account/projects"
+	resp, err := client.Get(url)
 	if err != nil {
-		return nil, fmt.Errorf("error fetching projects: %v", err)
+		return nil, apiRequestError("error fetching projects", url, err)
 	}
 	defer resp.Body.Close()
 
@@ -1768,114 +3682,681 @@ func getProjects(token *oauth2.Token) ([]project, error) {
 
 	err = json.Unmarshal(body, &response)
 	if err != nil {
-		return nil, fmt.Errorf("error parsing JSON response: %v", err)
+		return nil, fmt.Errorf("error parsing JSON response: %v", err)
+	}
+
+	return response.Data, nil
+}
+
+// describeProject returns "<name> (<id>)" for a project, or just the ID when
+// the name can't be found. The name comes from the API when fetch is set,
+// otherwise from the projects cache or the directory's project link.
+func describeProject(projectID string, fetch bool) string {
+	if p, ok := findProject(projectID, fetch); ok && p.Name != "" {
+		return fmt.Sprintf("%s (%s)", p.Name, p.ID)
+	}
+	if link, ok := getProjectLink(); ok && link.ProjectID == projectID {
+		return link.describe()
+	}
+	return projectID
+}
+
+// findProject looks projectID up among the user's projects, or only in the
+// projects cache when fetch is false
+func findProject(projectID string, fetch bool) (project, bool) {
+	if fetch {
+		if token, err := loadToken(); err == nil && token != nil {
+			if projects, err := getProjects(token); err == nil {
+				saveProjectsCache(projects)
+				for _, p := range projects {
+					if p.ID == projectID {
+						return p, true
+					}
+				}
+			}
+		}
+	}
+
+	if cache, ok := loadProjectsCache(); ok {
+		for _, p := range cache.Projects {
+			if p.ID == projectID {
+				return p, true
+			}
+		}
+	}
+	return project{}, false
+}
+
+func getProjectsMsg(token *oauth2.Token) tea.Msg {
+	client := authClient(token)
+	url := "https://api.basic.tech/account/projects"
+	resp, err := client.Get(url)
+	if err != nil {
+		return projectsMsg{err: apiRequestError("error fetching projects", url, err)}
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return projectsMsg{err: fmt.Errorf("error reading response body: %v", err)}
+	}
+
+	var response struct {
+		Data []project `json:"data"`
+	}
+
+	err = json.Unmarshal(body, &response)
+	if err != nil {
+		return projectsMsg{err: fmt.Errorf("error parsing JSON response: %v", err)}
+	}
+
+	return projectsMsg{projects: response.Data}
+}
+
+// performProjectsDelete deletes a project after the user types its name, or
+// right away with --yes
+func performProjectsDelete(projectID string) tea.Msg {
+	token, err := loadToken()
+	if err != nil || token == nil {
+		return errorScreenMsg{errorMessage: loggedOutError(err)}
+	}
+
+	projects, err := getProjects(token)
+	if err != nil {
+		return errorScreenMsg{errorMessage: err.Error()}
+	}
+	var selected *project
+	for i, p := range projects {
+		if p.ID == projectID {
+			selected = &projects[i]
+			break
+		}
+	}
+	if selected == nil {
+		return errorScreenMsg{errorMessage: fmt.Sprintf("project %s not found among your projects", projectID)}
+	}
+
+	remove := func() tea.Msg {
+		if err := deleteProject(token, selected.ID); err != nil {
+			return errorScreenMsg{errorMessage: err.Error()}
+		}
+		if cache, ok := loadProjectsCache(); ok {
+			remaining := []project{}
+			for _, p := range cache.Projects {
+				if p.ID != selected.ID {
+					remaining = append(remaining, p)
+				}
+			}
+			saveProjectsCache(remaining)
+		}
+		fmt.Printf("Deleted project %s (%s)\n", selected.Name, selected.ID)
+		return tea.Quit()
+	}
+
+	if assumeYes() {
+		return remove()
+	}
+	if !isInteractive() {
+		return errorScreenMsg{errorMessage: "deleting a project can't be undone, pass --yes to confirm"}
+	}
+
+	name := selected.Name
+	if name == "" {
+		name = selected.ID
+	}
+	return confirmMsg{
+		title:         fmt.Sprintf("Delete project %s?", name),
+		description:   fmt.Sprintf("This permanently deletes %s and all of its data.", selected.ID),
+		run:           remove,
+		cancelled:     "Delete cancelled",
+		typeToConfirm: name,
+	}
+}
+
+// pickProjectToDelete is 'basic projects delete' without a project ID
+func pickProjectToDelete() tea.Msg {
+	token, err := loadToken()
+	if err != nil || token == nil {
+		return errorScreenMsg{errorMessage: loggedOutError(err)}
+	}
+	projects, err := getProjects(token)
+	if err != nil {
+		return errorScreenMsg{errorMessage: err.Error()}
+	}
+	if len(projects) == 0 {
+		fmt.Println("You don't have any projects yet.")
+		return tea.Quit()
+	}
+	return projectPickMsg{
+		title:    "Which project do you want to delete?",
+		projects: projects,
+		run:      performProjectsDelete,
+	}
+}
+
+func deleteProject(token *oauth2.Token, projectID string) error {
+	url := "https://api.basic.tech/project/" + projectID
+	req, err := http.NewRequest(http.MethodDelete, url, nil)
+	if err != nil {
+		return fmt.Errorf("error creating request: %v", err)
+	}
+
+	resp, err := authClient(token).Do(req)
+	if err != nil {
+		return apiRequestError("error deleting project", url, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("received non-200 response deleting project: %d - %s", resp.StatusCode, string(body))
+	}
+	return nil
+}
+
+func performProjectsOpen() tea.Msg {
+	token, err := loadToken()
+	if err != nil || token == nil {
+		return errorScreenMsg{errorMessage: loggedOutError(err)}
+	}
+
+	args := positionalArgs()
+	pick := len(args) < 2 && !hasFlag("--latest")
+	if pick && !isInteractive() {
+		return usageMsg{text: "Usage: basic projects open <project_id> or basic projects open --latest"}
+	}
+
+	projects, err := getProjects(token)
+	if err != nil {
+		return errorScreenMsg{errorMessage: err.Error()}
+	}
+	if len(projects) == 0 {
+		fmt.Println("You don't have any projects yet. Create one with 'basic init'")
+		return tea.Quit()
+	}
+
+	var selected *project
+	if pick {
+		return projectPickMsg{
+			title:    "Which project do you want to open?",
+			projects: projects,
+			run: func(projectID string) tea.Msg {
+				fmt.Printf("Opening %s...\n", projectID)
+				if err := openBrowser("https://app.basic.tech/project/" + projectID); err != nil {
+					fmt.Printf("Error opening browser: %v\n", err)
+				}
+				return tea.Quit()
+			},
+		}
+	}
+	if hasFlag("--latest") {
+		selected = latestProject(projects)
+	} else {
+		for i, p := range projects {
+			if p.ID == args[1] {
+				selected = &projects[i]
+				break
+			}
+		}
+		if selected == nil {
+			return errorScreenMsg{errorMessage: fmt.Sprintf("project %s not found among your projects", args[1])}
+		}
+	}
+
+	fmt.Printf("Opening %s (%s)...\n", selected.Name, selected.ID)
+	if err := openBrowser("https://app.basic.tech/project/" + selected.ID); err != nil {
+		fmt.Printf("Error opening browser: %v\n", err)
+	}
+	return tea.Quit()
+}
+
+// printProjects prints projects as --json, --csv or a plain table for scripts
+func printProjects(projects []project) tea.Msg {
+	defaults := []string{"id", "name", "website", "created"}
+	if !hasFlag("--json", "--csv") {
+		defaults = []string{"id", "name", "website"}
+	}
+	fields, err := selectedProjectFields(defaults...)
+	if err != nil {
+		return errorScreenMsg{errorMessage: err.Error()}
+	}
+
+	switch {
+	case hasFlag("--json"):
+		printProjectsJSON(projects, fields)
+	case hasFlag("--csv"):
+		printProjectsCSV(projects, fields)
+	default:
+		printProjectsTable(projects, fields)
+	}
+	return tea.Quit()
+}
+
+// performProjectsSearch prints the projects whose name or ID contains the query
+func performProjectsSearch() tea.Msg {
+	args := positionalArgs()
+	if len(args) < 2 {
+		return usageMsg{text: "Usage: basic projects search <query>"}
+	}
+	query := strings.ToLower(strings.Join(args[1:], " "))
+
+	token, err := loadToken()
+	if err != nil || token == nil {
+		return errorScreenMsg{errorMessage: loggedOutError(err)}
+	}
+
+	projects, err := getProjects(token)
+	if err != nil {
+		return errorScreenMsg{errorMessage: err.Error()}
+	}
+
+	matches := []project{}
+	for _, p := range projects {
+		if strings.Contains(strings.ToLower(p.Name), query) || strings.Contains(strings.ToLower(p.ID), query) {
+			matches = append(matches, p)
+		}
+	}
+
+	if err := sortProjects(matches); err != nil {
+		return errorScreenMsg{errorMessage: err.Error()}
+	}
+
+	if hasFlag("--json") {
+		fields, err := selectedProjectFields("id", "name", "website", "created")
+		if err != nil {
+			return errorScreenMsg{errorMessage: err.Error()}
+		}
+		printProjectsJSON(matches, fields)
+		return tea.Quit()
+	}
+
+	fields, err := selectedProjectFields("id", "name", "website")
+	if err != nil {
+		return errorScreenMsg{errorMessage: err.Error()}
+	}
+
+	if len(matches) == 0 {
+		fmt.Printf("No projects matching %q\n", strings.Join(args[1:], " "))
+		return tea.Quit()
+	}
+
+	printProjectsTable(matches, fields)
+	return tea.Quit()
+}
+
+// projectField is a column of the non-interactive projects output
+type projectField struct {
+	name   string // as passed to --fields
+	key    string // json key and csv header
+	header string // table header
+	value  func(p project) string
+}
+
+var projectFields = []projectField{
+	{"id", "id", "ID", func(p project) string { return p.ID }},
+	{"name", "name", "NAME", func(p project) string { return p.Name }},
+	{"owner", "owner", "OWNER", func(p project) string { return p.Owner }},
+	{"website", "website", "WEBSITE", func(p project) string { return p.Website }},
+	{"created", "created_at", "CREATED", func(p project) string { return p.CreatedAt }},
+}
+
+// selectedProjectFields returns the columns chosen with --fields, in the
+// order given, or defaults when --fields isn't set
+func selectedProjectFields(defaults ...string) ([]projectField, error) {
+	names := defaults
+	if value := flagValue("--fields"); value != "" {
+		names = strings.Split(value, ",")
+	}
+
+	fields := []projectField{}
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		found := false
+		for _, f := range projectFields {
+			if f.name == name {
+				fields = append(fields, f)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("unknown field %q (use id, name, owner, website or created)", name)
+		}
+	}
+	return fields, nil
+}
+
+func printProjectsJSON(projects []project, fields []projectField) {
+	// built by hand so keys keep the --fields order
+	out := []json.RawMessage{}
+	for _, p := range projects {
+		var b bytes.Buffer
+		b.WriteString("{")
+		for i, f := range fields {
+			if i > 0 {
+				b.WriteString(",")
+			}
+			key, _ := json.Marshal(f.key)
+			value, _ := json.Marshal(f.value(p))
+			b.Write(key)
+			b.WriteString(":")
+			b.Write(value)
+		}
+		b.WriteString("}")
+		out = append(out, b.Bytes())
+	}
+	data, _ := json.MarshalIndent(out, "", "  ")
+	fmt.Println(string(data))
+}
+
+func printProjectsCSV(projects []project, fields []projectField) {
+	w := csv.NewWriter(os.Stdout)
+	header := []string{}
+	for _, f := range fields {
+		header = append(header, f.key)
+	}
+	w.Write(header)
+	for _, p := range projects {
+		row := []string{}
+		for _, f := range fields {
+			row = append(row, f.value(p))
+		}
+		w.Write(row)
+	}
+	w.Flush()
+}
+
+func printProjectsTable(projects []project, fields []projectField) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	headers := []string{}
+	for _, f := range fields {
+		headers = append(headers, f.header)
+	}
+	fmt.Fprintln(w, strings.Join(headers, "\t"))
+	for _, p := range projects {
+		values := []string{}
+		for _, f := range fields {
+			values = append(values, f.value(p))
+		}
+		fmt.Fprintln(w, strings.Join(values, "\t"))
+	}
+	w.Flush()
+}
+
+// sortProjects orders projects by --sort (name, created or id), reversed with
+// --reverse. Without --sort the API order is kept.
+func sortProjects(projects []project) error {
+	var less func(a, b project) bool
+	switch field := flagValue("--sort"); field {
+	case "":
+		return nil
+	case "name":
+		less = func(a, b project) bool { return strings.ToLower(a.Name) < strings.ToLower(b.Name) }
+	case "created":
+		less = func(a, b project) bool { return projectCreatedAt(a).Before(projectCreatedAt(b)) }
+	case "id":
+		less = func(a, b project) bool { return a.ID < b.ID }
+	default:
+		return fmt.Errorf("unknown sort field %q (use name, created or id)", field)
+	}
+
+	reverse := hasFlag("--reverse")
+	sort.SliceStable(projects, func(i, j int) bool {
+		if reverse {
+			return less(projects[j], projects[i])
+		}
+		return less(projects[i], projects[j])
+	})
+	return nil
+}
+
+// latestProject returns the most recently created project
+func latestProject(projects []project) *project {
+	latest := &projects[0]
+	for i := range projects {
+		if projectCreatedAt(projects[i]).After(projectCreatedAt(*latest)) {
+			latest = &projects[i]
+		}
+	}
+	return latest
+}
+
+func projectCreatedAt(p project) time.Time {
+	t, err := time.Parse(time.RFC3339, p.CreatedAt)
+	if err != nil {
+		return time.Time{}
+	}
+	return t
+}
+
+func performAccount() tea.Msg {
+	token, err := loadToken()
+	// fmt.Println("token", token, "err", err)
+	if err != nil || token == nil {
+		return errorScreenMsg{errorMessage: loggedOutError(err)}
+	}
+
+	if hasFlag("--json") {
+		return printAccountJSON(token)
+	}
+
+	if hasFlag("--token-expiry") {
+		fmt.Printf("Token expiry: %s (%s)\n", token.Expiry.Local().Format("2006-01-02 15:04:05"), tokenExpiresIn(token.Expiry))
+		return tea.Quit()
+	}
+
+	userInfo(token)
+	return tea.Quit()
+}
+
+// tokenExpiresIn describes the time left on a token, e.g. "expires in 42m"
+func tokenExpiresIn(expiry time.Time) string {
+	if expiry.IsZero() {
+		return "no expiry"
+	}
+	left := time.Until(expiry)
+	if left <= 0 {
+		return fmt.Sprintf("expired %s ago", shortDuration(-left))
+	}
+	return fmt.Sprintf("expires in %s", shortDuration(left))
+}
+
+// shortDuration formats d like 42m, 3h5m or 30s
+func shortDuration(d time.Duration) string {
+	switch {
+	case d >= time.Hour:
+		d = d.Round(time.Minute)
+		return fmt.Sprintf("%dh%dm", int(d.Hours()), int(d.Minutes())%60)
+	case d >= time.Minute:
+		return fmt.Sprintf("%dm", int(d.Round(time.Minute).Minutes()))
+	default:
+		return fmt.Sprintf("%ds", int(d.Round(time.Second).Seconds()))
+	}
+}
+
+func saveSchemaToConfig(schema string) error {
+	// prefer the config that has the schema, like readSchemaFromConfig
+	filename, err := schemaConfigFile()
+	if errors.Is(err, errNoSchemaFound) {
+		filename, err = findConfigFile()
+		if err != nil {
+			return fmt.Errorf("%w in config files", errNoSchemaFound)
+		}
+	} else if err != nil {
+		return err
+	}
+
+	content, err := os.ReadFile(filename)
+	if err != nil {
+		return fmt.Errorf("error reading %s: %v", filename, err)
+	}
+	format, _ := configFormatOf(filename)
+	// only the schema is replaced, keeping the rest of the file and the
+	// comments in the schema as they are
+	updated, err := format.writeSchema(string(content), schema)
+	if err != nil {
+		return fmt.Errorf("error parsing %s: %v", filename, err)
+	}
+	if err := os.WriteFile(filename, []byte(updated), 0644); err != nil {
+		return fmt.Errorf("error writing schema to %s: %v", filename, err)
+	}
+	return nil
+}
+
+// projectOverride is the project given with --project, or the project of the
+// --env environment, which status, push and pull use instead of the one in
+// the local config. main has already checked that --env exists.
+func projectOverride() string {
+	if name := flagValue("--env"); name != "" {
+		projectID, _ := environmentProject(name)
+		return projectID
+	}
+	return flagValue("--project")
+}
+
+// readLocalSchema reads the schema of the local config, pointed at the
+// --project override when there is one
+func readLocalSchema() (string, error) {
+	schema, err := readSchemaFromConfig()
+	override := projectOverride()
+	if err != nil || override == "" {
+		return schema, err
+	}
+
+	schemaData, err := parseSchemaJSON(schema)
+	if err != nil {
+		return "", err
+	}
+	schemaData["project_id"] = override
+	formatted, err := json.MarshalIndent(schemaData, "\t", "\t")
+	if err != nil {
+		return "", fmt.Errorf("error formatting schema JSON: %v", err)
 	}
-
-	return response.Data, nil
+	return string(formatted), nil
 }
 
-func getProjectsMsg(token *oauth2.Token) tea.Msg {
-	client := oauthConfig.Client(context.Background(), token)
-	resp, err := client.Get("https://api.basic.tech/account/projects")
+// configProjectID returns the project_id from the config block of the local
+// config, for configs that don't have a schema to read it from
+func configProjectID() (string, error) {
+	filename, err := findConfigFile()
 	if err != nil {
-		return projectsMsg{err: fmt.Errorf("error fetching projects: %v", err)}
+		return "", err
 	}
-	defer resp.Body.Close()
-
-	body, err := io.ReadAll(resp.Body)
+	content, err := os.ReadFile(filename)
 	if err != nil {
-		return projectsMsg{err: fmt.Errorf("error reading response body: %v", err)}
-	}
-
-	var response struct {
-		Data []project `json:"data"`
+		return "", fmt.Errorf("error reading %s: %v", filename, err)
 	}
 
-	err = json.Unmarshal(body, &response)
+	format, _ := configFormatOf(filename)
+	projectID, err := format.readProjectID(string(content))
 	if err != nil {
-		return projectsMsg{err: fmt.Errorf("error parsing JSON response: %v", err)}
+		return "", fmt.Errorf("error parsing %s: %v", filename, err)
 	}
-
-	return projectsMsg{projects: response.Data}
+	if projectID == "" {
+		return "", fmt.Errorf("no project_id found in %s", filename)
+	}
+	return projectID, nil
 }
 
-func performAccount() tea.Msg {
-	token, err := loadToken()
-	// fmt.Println("token", token, "err", err)
-	if err != nil || token == nil {
-		fmt.Println("Not logged in. Please use the 'login' command to authenticate.")
-		return tea.Quit()
+// findConfigFile returns the first basic config file found in the current directory
+func findConfigFile() (string, error) {
+	for _, filename := range configFileNames {
+		if _, err := os.Stat(filename); err == nil {
+			return filename, nil
+		}
 	}
 
-	userInfo(token)
-	return tea.Quit()
+	return "", fmt.Errorf("no basic.config.ts, .js, .json or .yaml found in this directory")
 }
 
-func saveSchemaToConfig(schema string) error {
-	configFiles := []string{"basic.config.ts", "basic.config.js"}
-
-	for _, filename := range configFiles {
-		content, err := os.ReadFile(filename)
-		if err == nil {
-			// Look for schema = { ... } or schema: { ... } pattern
-			re := regexp.MustCompile(`(?s)(schema[:\s]+=?\s*)({.*?})([\s;]*(?:export|\z))`)
-			matches := re.FindSubmatch(content)
-
-			if len(matches) > 0 {
-				// Replace just the schema object part while keeping the surrounding syntax
-				newContent := re.ReplaceAllString(string(content), "${1}"+schema+"${3}")
-
-				err = os.WriteFile(filename, []byte(newContent), 0644)
-				if err != nil {
-					return fmt.Errorf("error writing schema to %s: %v", filename, err)
-				}
-				return nil
-			}
+// schemaConfigFile returns the first config in the current directory that
+// has a schema
+func schemaConfigFile() (string, error) {
+	for _, filename := range configFileNames {
+		if _, err := os.Stat(filename); err != nil {
+			continue
+		}
+		_, _, err := readSchemaSource(filename)
+		if errors.Is(err, errNoSchemaFound) {
+			continue
 		}
+		return filename, err
 	}
-
-	return fmt.Errorf("no schema found in config files")
+	return "", fmt.Errorf("%w in config files", errNoSchemaFound)
 }
 
-func readSchemaFromConfig() (string, error) {
-	configFiles := []string{"basic.config.ts", "basic.config.js"}
+// getLocalProjectID returns the project_id from the schema in the local config file
+func getLocalProjectID() (string, error) {
+	schema, err := readSchemaFromConfig()
+	if err != nil {
+		return "", err
+	}
 
-	for _, filename := range configFiles {
-		content, err := os.ReadFile(filename)
-		if err == nil {
-			// Look for schema = { ... } or schema: { ... } pattern
-			// Use (?s) flag to make dot match newlines
-			re := regexp.MustCompile(`(?s)schema[:\s]+=?\s*({.*?})[\s;]*(?:export|\z)`)
-			matches := re.FindSubmatch(content)
+	var schemaData map[string]interface{}
+	if err := json.Unmarshal([]byte(schema), &schemaData); err != nil {
+		return "", fmt.Errorf("error parsing schema: %v", err)
+	}
 
-			if len(matches) > 1 {
-				schemaJSON := matches[1]
-				jsonStr := string(schemaJSON)
+	projectID, ok := schemaData["project_id"].(string)
+	if !ok || projectID == "" {
+		return "", fmt.Errorf("no project ID found in schema")
+	}
+	return projectID, nil
+}
 
-				// First convert single quotes to double quotes
-				jsonStr = strings.ReplaceAll(jsonStr, "'", "\"")
+var errNoSchemaFound = errors.New("no schema found")
 
-				// Add quotes to unquoted object keys
-				re := regexp.MustCompile(`([{,]\s*)([a-zA-Z_][a-zA-Z0-9_]*)\s*:`)
-				jsonStr = re.ReplaceAllString(jsonStr, `$1"$2":`)
+func readSchemaFromConfig() (string, error) {
+	filename, err := schemaConfigFile()
+	if err != nil {
+		return "", err
+	}
+	return readSchemaFromFile(filename)
+}
 
-				// Parse and re-marshal to ensure valid JSON
-				var parsed map[string]interface{}
-				if err := json.Unmarshal([]byte(jsonStr), &parsed); err != nil {
-					return "", fmt.Errorf("invalid schema JSON in %s: %v", filename, err)
-				}
+// readSchemaFromFile reads the schema from a basic config file, or from a
+// plain JSON or YAML file containing just the schema
+func readSchemaFromFile(filename string) (string, error) {
+	source, _, err := readSchemaSource(filename)
+	if err != nil {
+		return "", err
+	}
 
-				prettyJSON, err := json.MarshalIndent(parsed, "", "  ")
-				if err != nil {
-					return "", fmt.Errorf("error formatting schema JSON: %v", err)
-				}
+	// Parse and re-marshal to ensure valid JSON
+	var parsed map[string]interface{}
+	if err := json.Unmarshal([]byte(source), &parsed); err != nil {
+		return "", fmt.Errorf("invalid schema JSON in %s: %v", filename, err)
+	}
 
-				return string(prettyJSON), nil
-			}
-		}
+	prettyJSON, err := json.MarshalIndent(parsed, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("error formatting schema JSON: %v", err)
+	}
+	return string(prettyJSON), nil
+}
+
+// readSchemaSource returns the schema in filename as JSON that's laid out
+// like the file, and the line of the file it starts on, so positions in it
+// can be reported as lines of the file
+func readSchemaSource(filename string) (string, int, error) {
+	content, err := os.ReadFile(filename)
+	if err != nil {
+		return "", 0, fmt.Errorf("error reading %s: %v", filename, err)
 	}
 
-	return "", fmt.Errorf("no schema found in config files")
+	format, ok := configFormatOf(filename)
+	if !ok {
+		// read anything else as a JS/TS module
+		format = configFormats[0]
+	}
+	source, firstLine, err := format.readSchema(string(content))
+	if errors.Is(err, errNoSchemaFound) {
+		return "", 0, fmt.Errorf("%w in %s", errNoSchemaFound, filename)
+	}
+	if err != nil {
+		return "", 0, fmt.Errorf("error parsing %s: %v", filename, err)
+	}
+	return source, firstLine, nil
 }
 
 // -----------------------------//
@@ -1889,15 +4370,32 @@ func performLogin() tea.Msg {
 		return tea.Quit()
 	}
 
+	return runLoginFlow()
+}
+
+// performReauth runs the login flow even if the current token is still valid,
+// replacing the stored token
+func performReauth() tea.Msg {
+	return runLoginFlow()
+}
+
+func runLoginFlow() tea.Msg {
+	// bind before sending anyone to the browser, so a busy port is reported
+	// up front instead of leaving the login waiting forever
+	listener, err := net.Listen("tcp", loginCallbackAddr)
+	if err != nil {
+		return errorScreenMsg{errorMessage: fmt.Sprintf("login failed: %v", callbackListenError(err))}
+	}
+
 	url := oauthConfig.AuthCodeURL(oauthState)
 	fmt.Printf("Please visit this URL to log in: %s\n", url)
 
-	server := &http.Server{Addr: ":8080"}
+	server := &http.Server{}
 	http.HandleFunc("/callback", handleCallback(server))
 
 	go func() {
-		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-			fmt.Printf("HTTP server error: %v\n", err)
+		if err := server.Serve(listener); err != nil && err != http.ErrServerClosed {
+			authDone <- fmt.Errorf("callback server error: %v", err)
 		}
 	}()
 
@@ -1906,12 +4404,41 @@ func performLogin() tea.Msg {
 		fmt.Printf("Error opening browser: %v\n", err)
 	}
 
-	<-authDone
+	if err := <-authDone; err != nil {
+		return errorScreenMsg{errorMessage: fmt.Sprintf("login failed: %v", err)}
+	}
 
+	if account, ok := loadAccountInfo(); ok && account.Email != "" {
+		fmt.Printf("Login successful! Hello %s :)\n", account.Email)
+		return tea.Quit()
+	}
 	fmt.Println("Login successful! Hello :)")
 	return tea.Quit()
 }
 
+// loginCallbackAddr is where the OAuth redirect lands, see oauthConfig.RedirectURL
+const loginCallbackAddr = ":8080"
+
+// callbackListenError explains why the login callback server couldn't start,
+// recognising a stale 'basic login' that is still holding the port
+func callbackListenError(err error) error {
+	// an unfinished login answers callbacks without our state with "Invalid state"
+	client := &http.Client{Timeout: 2 * time.Second}
+	if resp, probeErr := client.Get("http://localhost:8080/callback?state=probe&code=probe"); probeErr == nil {
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+		if resp.StatusCode == http.StatusBadRequest && strings.TrimSpace(string(body)) == "Invalid state" {
+			return fmt.Errorf("port 8080 is already in use by an earlier 'basic login' that never finished. " +
+				"Stop that process (e.g. 'kill $(lsof -ti :8080)') and try again")
+		}
+	}
+
+	if errors.Is(err, syscall.EADDRINUSE) {
+		return fmt.Errorf("port 8080 is already in use by another program. Free it and try again")
+	}
+	return fmt.Errorf("could not start the login callback server: %v", err)
+}
+
 func handleCallback(server *http.Server) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		if r.URL.Path != "/callback" {
@@ -1931,28 +4458,36 @@ func handleCallback(server *http.Server) http.HandlerFunc {
 			return
 		}
 
-		token, err := oauthConfig.Exchange(context.Background(), code)
+		// stop the server and tell runLoginFlow why login failed
+		fail := func(message string, err error) {
+			http.Error(w, fmt.Sprintf("%s: %v", message, err), http.StatusInternalServerError)
+			go func() {
+				server.Shutdown(context.Background())
+				authDone <- fmt.Errorf("%s: %v", strings.ToLower(message), err)
+			}()
+		}
+
+		token, err := exchangeCode(code)
 		if err != nil {
-			http.Error(w, "Failed to exchange token", http.StatusInternalServerError)
+			fail("Failed to exchange token", err)
 			return
 		}
 
 		refreshToken, ok := token.Extra("refresh").(string)
 		if !ok {
-			fmt.Printf("Failed to get refresh token: %v\n", err)
-			http.Error(w, "Failed to get refresh token", http.StatusInternalServerError)
+			fail("Failed to get refresh token", fmt.Errorf("token response has no refresh token"))
 			return
 		}
 		token.RefreshToken = refreshToken
 
 		err = saveToken(token)
 		if err != nil {
-			fmt.Printf("Failed to save token: %v\n", err)
-			http.Error(w, "Failed to save token", http.StatusInternalServerError)
+			fail("Failed to save token", err)
 			return
 		}
 
-		// loggedInUser = "Authenticated User" // save user info
+		// remember who logged in, so commands don't have to ask the API
+		fetchAccountInfo(token)
 
 		w.Header().Set("Content-Type", "text/html; charset=utf-8")
 
@@ -1963,45 +4498,104 @@ func handleCallback(server *http.Server) http.HandlerFunc {
 		go func() {
 			time.Sleep(2 * time.Second)
 			server.Shutdown(context.Background())
-			authDone <- true
+			authDone <- nil
 		}()
 	}
 }
 
+// exchangeCode trades the authorization code for a token, retrying network
+// errors. Errors returned by the token endpoint aren't retried since the code
+// can only be used once.
+func exchangeCode(code string) (*oauth2.Token, error) {
+	const attempts = 3
+
+	var err error
+	for i := 0; i < attempts; i++ {
+		if i > 0 {
+			time.Sleep(time.Duration(i) * time.Second)
+		}
+
+		var token *oauth2.Token
+		token, err = oauthConfig.Exchange(apiContext(), code)
+		if err == nil {
+			return token, nil
+		}
+
+		var retrieveErr *oauth2.RetrieveError
+		if errors.As(err, &retrieveErr) {
+			return nil, err
+		}
+	}
+	return nil, fmt.Errorf("after %d attempts: %v", attempts, err)
+}
+
 func performLogout() tea.Msg {
+	if token, err := readStoredToken(); err == nil && token == nil {
+		return errorScreenMsg{errorMessage: "you're not logged in", code: "not_logged_in"}
+	}
+
 	err := deleteToken()
 	if err != nil {
-		fmt.Printf("Error removing token: %v\n", err)
+		return errorScreenMsg{errorMessage: fmt.Sprintf("error removing token: %v", err)}
+	}
+	if account, ok := loadAccountInfo(); ok && account.Email != "" {
+		fmt.Printf("Logged out %s successfully\n", account.Email)
 	} else {
 		fmt.Println("Logged out successfully")
 	}
+	if err := deleteAccountInfo(); err != nil {
+		fmt.Printf("Error removing saved account: %v\n", err)
+	}
+
+	// don't leave this account's data behind for the next user of the machine
+	if cleared := clearProfileCaches(); len(cleared) > 0 {
+		fmt.Printf("Cleared cached %s\n", strings.Join(cleared, ", "))
+	}
 	return tea.Quit()
 }
 
-func userInfo(token *oauth2.Token) {
-	client := oauthConfig.Client(context.Background(), token)
-
-	resp, err := client.Get("https://api.basic.tech/auth/userInfo")
-	if err != nil {
-		fmt.Printf("Error fetching user info: %v\n", err)
-		os.Exit(1)
+// clearProfileCaches removes the active profile's cache files, returning a
+// description of each one that was removed
+func clearProfileCaches() []string {
+	caches := []struct {
+		description string
+		path        func() (string, error)
+	}{
+		{"projects", getProjectsCacheFilePath},
+		{"remote schemas", getRemoteSchemaCacheFilePath},
+		{"validation results", getValidationCacheFilePath},
 	}
-	defer resp.Body.Close()
 
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		fmt.Printf("Error reading response body: %v\n", err)
-		os.Exit(1)
+	cleared := []string{}
+	for _, cache := range caches {
+		path, err := cache.path()
+		if err != nil {
+			continue
+		}
+		if err := os.Remove(path); err == nil {
+			cleared = append(cleared, cache.description)
+		} else if !os.IsNotExist(err) {
+			fmt.Printf("Error removing cached %s: %v\n", cache.description, err)
+		}
 	}
+	return cleared
+}
 
-	var userInfo map[string]interface{}
-	err = json.Unmarshal(body, &userInfo)
+// userInfo prints who is logged in, refreshing the saved account. If the API
+// can't be reached the saved account is shown instead.
+func userInfo(token *oauth2.Token) {
+	account, err := fetchAccountInfo(token)
 	if err != nil {
-		fmt.Printf("Error parsing JSON response: %v\n", err)
-		os.Exit(1)
+		saved, ok := loadAccountInfo()
+		if !ok {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		fmt.Printf("Could not refresh your account (%v), showing the saved one\n", err)
+		account = saved
 	}
 
-	fmt.Println("Logged in user:", userInfo["email"])
+	fmt.Println("Logged in user:", account.Email)
 }
 
 func openBrowser(url string) error {
@@ -2023,21 +4617,25 @@ func openBrowser(url string) error {
 //   🔑 KEYRING & TOKEN METHODS   //
 // ----------------------------- //
 
-// func getKeyring() (keyring.Keyring, error) {
-// 	return keyring.Open(keyring.Config{
-// 		ServiceName: keyringService,
-// 	})
-// }
+// getBasicCliDir returns the ~/.basic-cli directory
+func getBasicCliDir() (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(homeDir, basicCliDirName), nil
+}
 
 func getTokenFilePath() (string, error) {
-	homeDir, err := os.UserHomeDir()
+	profileDir, err := getProfileDir(activeProfile())
 	if err != nil {
 		return "", err
 	}
-	basicCliDir := filepath.Join(homeDir, basicCliDirName)
-	return filepath.Join(basicCliDir, tokenFileName), err
+	return filepath.Join(profileDir, tokenFileName), nil
 }
 
+// saveToken stores the token in the OS keychain, or in the token file when
+// there's no keychain to use
 func saveToken(token *oauth2.Token) error {
 	tokenJSON, err := json.Marshal(token)
 	if err != nil {
@@ -2049,28 +4647,48 @@ func saveToken(token *oauth2.Token) error {
 		return err
 	}
 
-	// Create the .basic-cli directory if it doesn't exist
+	// Create the profile directory if it doesn't exist, even when the token
+	// goes to the keychain, since that's how profiles are listed
 	dir := filepath.Dir(tokenFilePath)
 	if err := os.MkdirAll(dir, 0700); err != nil {
 		return err
 	}
 
+	if keyring := systemKeyring(); keyring != nil {
+		if err := keyring.set(keyringAccount(), string(tokenJSON)); err == nil {
+			// don't leave an older plaintext copy behind
+			return removeTokenFile()
+		}
+	}
+
 	return os.WriteFile(tokenFilePath, tokenJSON, 0600)
 }
 
 // load token from local basic config file
 func loadToken() (*oauth2.Token, error) {
-	tokenFilePath, err := getTokenFilePath()
-	if err != nil {
-		fmt.Println("error getting token file path", err)
+	token, err := readStoredToken()
+	if err != nil || token == nil {
 		return nil, err
 	}
 
-	tokenData, err := os.ReadFile(tokenFilePath)
-	if err != nil {
-		if os.IsNotExist(err) {
-			return nil, nil
+	if token.Expiry.Before(time.Now()) {
+		newToken, err := refreshAccessToken(token)
+		if errors.Is(err, errNoRefreshToken) {
+			return nil, err
+		}
+		if err != nil {
+			return nil, fmt.Errorf("token has expired and %v", err)
 		}
+		return newToken, nil
+	}
+
+	return token, nil
+}
+
+// readStoredToken reads the saved token as-is, without refreshing it
+func readStoredToken() (*oauth2.Token, error) {
+	tokenData, err := readTokenData()
+	if err != nil || tokenData == nil {
 		return nil, err
 	}
 
@@ -2080,29 +4698,137 @@ func loadToken() (*oauth2.Token, error) {
 		return nil, err
 	}
 
-	if token.Expiry.Before(time.Now()) {
-		newToken, err := oauthConfig.Exchange(context.Background(), token.RefreshToken)
-		if err != nil {
-			return nil, fmt.Errorf("token has expired and refresh failed: %v", err)
-		}
+	return &token, nil
+}
 
-		refreshToken, ok := newToken.Extra("refresh").(string)
-		if !ok {
-			return nil, fmt.Errorf("failed to get refresh token: %v", err)
-		}
-		newToken.RefreshToken = refreshToken
+// loggedOutError is the message for a failed loadToken: usually "log in",
+// unless the stored session can no longer be refreshed
+func loggedOutError(err error) string {
+	if errors.Is(err, errNoRefreshToken) {
+		return err.Error()
+	}
+	return loggedOutMessage
+}
+
+// errNoRefreshToken is returned for tokens saved before refresh tokens were
+// stored, which can only be replaced by logging in again
+var errNoRefreshToken = errors.New("your session can't be refreshed, please run 'basic login'")
+
+// refreshAccessToken exchanges the refresh token for a new access token and saves it
+func refreshAccessToken(token *oauth2.Token) (*oauth2.Token, error) {
+	if token.RefreshToken == "" {
+		return nil, errNoRefreshToken
+	}
+
+	newToken, err := oauthConfig.Exchange(apiContext(), token.RefreshToken)
+	if err != nil {
+		return nil, fmt.Errorf("refresh failed: %v", err)
+	}
+
+	refreshToken, ok := newToken.Extra("refresh").(string)
+	if !ok {
+		return nil, fmt.Errorf("failed to get refresh token")
+	}
+	newToken.RefreshToken = refreshToken
+
+	if err := saveToken(newToken); err != nil {
+		return nil, fmt.Errorf("failed to save refreshed token: %v", err)
+	}
+
+	return newToken, nil
+}
+
+func performTokenRefresh() tea.Msg {
+	token, err := readStoredToken()
+	if err != nil || token == nil {
+		return errorScreenMsg{errorMessage: loggedOutError(err)}
+	}
+
+	newToken, err := refreshAccessToken(token)
+	if err != nil {
+		return errorScreenMsg{errorMessage: fmt.Sprintf("error refreshing token: %v", err)}
+	}
+
+	fmt.Printf("Token refreshed! New expiry: %s (in %s)\n",
+		newToken.Expiry.Local().Format("2006-01-02 15:04:05"),
+		time.Until(newToken.Expiry).Round(time.Second))
+	return tea.Quit()
+}
+
+// printAuthToken implements 'basic auth token [--header]'. It runs outside
+// the UI so the output can be captured with $(...), and returns the exit code.
+func printAuthToken() int {
+	if args := positionalArgs(); len(args) == 0 || args[0] != "token" {
+		fmt.Fprintln(os.Stderr, "Usage: basic auth token [--header]")
+		return exitUsage
+	}
+
+	token, err := loadToken()
+	if err != nil || token == nil {
+		fmt.Fprintln(os.Stderr, loggedOutError(err))
+		return exitNotLoggedIn
+	}
+
+	// stderr so the warning is seen but doesn't end up in captured output
+	fmt.Fprintln(os.Stderr, "Warning: this is your secret access token. Don't share it or commit it anywhere.")
 
-		if err := saveToken(newToken); err != nil {
-			return nil, fmt.Errorf("failed to save refreshed token: %v", err)
+	if hasFlag("--header") {
+		fmt.Printf("Authorization: Bearer %s\n", token.AccessToken)
+	} else {
+		fmt.Println(token.AccessToken)
+	}
+	return exitOK
+}
+
+// readTokenData returns the saved token JSON from the keychain or, failing
+// that, the token file, which is then moved into the keychain. It's nil when
+// there's no saved token.
+func readTokenData() ([]byte, error) {
+	keyring := systemKeyring()
+	var keyringErr error
+	if keyring != nil {
+		secret, err := keyring.get(keyringAccount())
+		if err == nil {
+			return []byte(secret), nil
+		}
+		if !errors.Is(err, errNotInKeyring) {
+			// a locked or unreachable keychain isn't the same as logged out
+			keyringErr = err
 		}
+	}
 
-		return newToken, nil
+	tokenFilePath, err := getTokenFilePath()
+	if err != nil {
+		fmt.Println("error getting token file path", err)
+		return nil, err
 	}
 
-	return &token, nil
+	tokenData, err := os.ReadFile(tokenFilePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, keyringErr
+		}
+		return nil, err
+	}
+
+	// logged in before the keychain was used
+	if keyring != nil && keyringErr == nil && keyring.set(keyringAccount(), string(tokenData)) == nil {
+		removeTokenFile()
+	}
+	return tokenData, nil
 }
 
+// deleteToken removes the token from both the keychain and the token file
 func deleteToken() error {
+	if keyring := systemKeyring(); keyring != nil {
+		if err := keyring.delete(keyringAccount()); err != nil {
+			return err
+		}
+	}
+	return removeTokenFile()
+}
+
+func removeTokenFile() error {
 	tokenFilePath, err := getTokenFilePath()
 	if err != nil {
 		return err
@@ -2119,6 +4845,95 @@ func deleteToken() error {
 //   🦄 UTIL FUNCTIONS           //
 // ----------------------------- //
 
+// cliArgs holds everything after the command name, e.g. ["watch", "--push"]
+var cliArgs []string
+
+// valueFlags are flags that take a value, e.g. --limit 10 or --limit=10
+var valueFlags = map[string]bool{
+	"--since":        true,
+	"--limit":        true,
+	"--file":         true,
+	"--ignore":       true,
+	"--config-out":   true,
+	"--api-timeout":  true,
+	"--format":       true,
+	"--out":          true,
+	"--sort":         true,
+	"--profile":      true,
+	"--schema-index": true,
+	"--fields":       true,
+	"--backup-dir":   true,
+	"--keep":         true,
+	"--channel":      true,
+	"--role":         true,
+	"--project":      true,
+	"--env":          true,
+}
+
+// hasFlag reports whether any of the given flags was passed
+func hasFlag(names ...string) bool {
+	for _, arg := range cliArgs {
+		for _, name := range names {
+			if arg == name || strings.HasPrefix(arg, name+"=") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// flagValue returns the value passed to a flag, or "" if it wasn't set
+func flagValue(name string) string {
+	for i, arg := range cliArgs {
+		if arg == name && i+1 < len(cliArgs) {
+			return cliArgs[i+1]
+		}
+		if strings.HasPrefix(arg, name+"=") {
+			return strings.TrimPrefix(arg, name+"=")
+		}
+	}
+	return ""
+}
+
+// flagValues returns every value passed to a repeatable flag
+func flagValues(name string) []string {
+	var values []string
+	for i, arg := range cliArgs {
+		if arg == name && i+1 < len(cliArgs) {
+			values = append(values, cliArgs[i+1])
+		} else if strings.HasPrefix(arg, name+"=") {
+			values = append(values, strings.TrimPrefix(arg, name+"="))
+		}
+	}
+	return values
+}
+
+// positionalArgs returns the arguments that are not flags or flag values
+func positionalArgs() []string {
+	var args []string
+	for i := 0; i < len(cliArgs); i++ {
+		arg := cliArgs[i]
+		if valueFlags[arg] {
+			i++
+			continue
+		}
+		if !strings.HasPrefix(arg, "-") {
+			args = append(args, arg)
+		}
+	}
+	return args
+}
+
+// hasUncommittedChanges reports whether filename has uncommitted git changes.
+// It's false outside a git repo or when git isn't installed.
+func hasUncommittedChanges(filename string) bool {
+	out, err := exec.Command("git", "status", "--porcelain", "--", filename).Output()
+	if err != nil {
+		return false
+	}
+	return strings.TrimSpace(string(out)) != ""
+}
+
 func generateSlugFromName(name string) string {
 	slug := strings.ToLower(name)
 	slug = strings.ReplaceAll(slug, " ", "-")
@@ -2147,19 +4962,128 @@ const (
 	similarityThreshold = 0.4
 )
 
+type commandInfo struct {
+	name        string
+	description string
+}
+
+// commandList is shown by 'basic help' and the interactive menu
+var commandList = []commandInfo{
+	{"account", "Show account information (--token-expiry to show when your token expires, --json)"},
+	{"account usage", "Show your plan limits and how much of them you use (--json)"},
+	{"account switch", "Change the active profile (or pass the profile name)"},
+	{"profile", "List your profiles, one per account you've logged in to with 'basic login --profile <name>'"},
+	{"profile use <name>", "Make <name> the active profile"},
+	{"login", "login with your basic account"},
+	{"logout", "logout from your basic account"},
+	{"reauth", "login again, replacing your current token"},
+	{"token refresh", "Refresh your access token now and show the new expiry"},
+	{"auth token", "Print your access token (--header for an Authorization header line)"},
+	{"status", "Show schema status in current project (--explain for next steps, --prompt for a shell prompt, --no-fetch to use the cached remote schema, --summary for a final RESULT line, --project <id> to check another project, --env <name> to check an environment's project, --json)"},
+	{"push", "Push schema to remote (--confirm-remote to wait for the new version to show up, --ci to skip the confirmation, --dry-run to preview the changes and new version without pushing, --project <id> to push the local schema to another project, --env <name> to push to an environment's project)"},
+	{"pull", "Pull schema from remote (--check to only report drift, --ignore <path> to skip paths, --schema-index <n> to pick a schema record, --backup-dir <dir> and --keep <n> for config backups, --project <id> to pull another project, creating the config if there's none, --env <name> to pull an environment's project)"},
+	{"projects", "list your projects (--limit <n> to show more than 50, --json/--csv, --fields id,name,..., --sort name|created|id, --reverse)"},
+	{"projects open [id]", "Open a project in the browser (--latest for the newest project, no ID to pick one)"},
+	{"projects search <query>", "Find projects by name or ID (--json)"},
+	{"projects delete [id]", "Delete a project after typing its name to confirm (--yes to skip, no ID to pick one)"},
+	{"init", "Create a new project or import an existing project, with a TypeScript, JavaScript, JSON or YAML config (--config-out <path> to choose the file)"},
+	{"validate", "Validate the local schema (--file <path>, --stdin or - to read JSON from stdin, --all for every config below here, --json, --watch, --against-remote to catch breaking changes, --offline to check without the API, --quiet to only print errors, --summary for a final RESULT line)"},
+	{"watch", "Parse, validate and check the status of the schema on every config save, waiting for a config if there is none (--offline to skip the API)"},
+	{"history", "List published schema versions (--since, --limit, --json)"},
+	{"diff", "Show field-level differences between the local and remote schema (--ignore <path>, --project <id> to compare with another project, --env <name> to compare with an environment's project, --json)"},
+	{"data [table]", "Browse the records of a table, a page at a time (--limit <n> per page, --json for the first page)"},
+	{"data insert <table> [json]", "Add a record (JSON from the argument, --file, --stdin or your $EDITOR)"},
+	{"data get <table> <id>", "Print a record as JSON"},
+	{"data update <table> <id> [json]", "Change a record's fields (JSON as for insert; the editor starts from the current record)"},
+	{"data delete <table> <id>", "Delete a record (--yes to skip the confirmation)"},
+	{"schema watch", "Validate schema on every config save (--push to also push)"},
+	{"schema unused", "List tables with no fields defined"},
+	{"schema import <file>", "Replace the config's schema with a JSON schema file"},
+	{"schema describe <table>", "Show a table's fields, types and constraints"},
+	{"schema copy <from> <to>", "Copy one project's schema to another (--yes to skip the confirmation)"},
+	{"schema export", "Export the schema as JSON Schema or OpenAPI (--format jsonschema|openapi, --out <file>)"},
+	{"schema init-remote", "Publish the local schema as the first version of a project with no schema yet"},
+	{"schema history", "List published schema versions, enter restores one (--since, --limit, --json)"},
+	{"schema rollback <version>", "Restore a published schema version locally (--push to also publish it, --yes to skip the confirmation)"},
+	{"generate types", "Write TypeScript interfaces for every table to basic.types.ts (--out <file>, --file <config>, --watch to regenerate on every save)"},
+	{"generate zod", "Write Zod schemas for every table to basic.zod.ts (--out <file>, --file <config>, --watch to regenerate on every save)"},
+	{"keys list", "List the API keys of the project, masked (--json)"},
+	{"keys create [name]", "Create an API key and copy it to the clipboard (--json prints it in full)"},
+	{"keys revoke <key_id>", "Revoke an API key (--yes to skip the confirmation)"},
+	{"team list", "List the project's members and their roles, 'r' changes a role (--json)"},
+	{"team invite <email>", "Invite someone to the project by email (--role admin|member, default member)"},
+	{"team remove <email>", "Remove a member from the project (--yes to skip the confirmation)"},
+	{"migrate plan", "List the ordered steps from the remote schema to the local one, warning about steps that lose data (--json)"},
+	{"migrate apply", "Push the migration steps one at a time, confirming each (--yes to apply them all)"},
+	{"env list", "List the environments and their projects (--json)"},
+	{"env add <name> <project_id>", "Map an environment to a project, stored in .basic/environments.json next to the config"},
+	{"env remove <name>", "Remove an environment"},
+	{"version", "Show CLI version (--json for tooling)"},
+	{"update", "Update CLI to the latest version (--channel beta to get pre-releases, --channel stable to go back)"},
+	{"debug", "Show Basic config directory location"},
+	{"doctor", "Check your setup for common problems, like a skewed system clock"},
+	{"feedback", "Open a GitHub issue prefilled with your CLI version and platform"},
+}
+
+// commandAliases are shorter names for common commands, resolved before
+// anything else looks at the command
+var commandAliases = map[string]string{
+	"ls":     "projects",
+	"whoami": "account",
+	"me":     "account",
+}
+
+// hiddenCommandList is only shown by 'basic help --all'
+var hiddenCommandList = []commandInfo{
+	{"help [command]", "Show this help, or the help of one command (--all to include hidden commands)"},
+	{"hi", "Say hi"},
+	{"logo", "Print the Basic logo"},
+}
+
+// globalFlagList is shown by 'basic help' and works with every command
+var globalFlagList = []commandInfo{
+	{"--help, -h", "Show the help of the command"},
+	{"--api-timeout <seconds>", "How long to wait for API requests (default 30)"},
+	{"--force-color", "Keep colors when output is piped (or set CLICOLOR_FORCE=1)"},
+	{"--yes, -y", "Answer yes to every confirmation, including ones that overwrite files"},
+	{"--profile <name>", "Use this profile instead of the active one"},
+	{"--ci", "Never prompt or draw the interactive UI, for CI and scripts"},
+	{"--json", "Print machine-readable JSON without the interactive UI; errors go to stderr as {\"error\": ..., \"code\": ...} with a non-zero exit"},
+}
+
 // Add these new commands slice and helper functions
 var commands = []string{
 	"account",
+	"profile",
 	"login",
 	"logout",
+	"reauth",
+	"token",
+	"auth",
 	"status",
 	"projects",
 	"init",
 	"version",
-	"config",
 	"help",
 	"push",
 	"pull",
+	"schema",
+	"generate",
+	"watch",
+	"env",
+	"migrate",
+	"keys",
+	"team",
+	"history",
+	"diff",
+	"data",
+	"validate",
+	"update",
+	"debug",
+	"doctor",
+	"feedback",
+	"hi",
+	"logo",
 }
 
 // Calculate similarity between two strings using Levenshtein distance
@@ -2207,6 +5131,16 @@ func levenshteinDistance(s1, s2 string) int {
 	return matrix[len(s1)][len(s2)]
 }
 
+// unknownCommandError is the --json error for a command that doesn't exist,
+// with the commands it's close to
+func unknownCommandError(command string, suggestions []string) string {
+	message := fmt.Sprintf("unknown command: %s", command)
+	if len(suggestions) > 0 {
+		message += fmt.Sprintf(", did you mean: %s?", strings.Join(suggestions, ", "))
+	}
+	return message
+}
+
 func findSimilarCommands(input string) []string {
 	var suggestions []string
 	for _, cmd := range commands {
@@ -0,0 +1,468 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+const migrateUsage = `Usage:
+  basic migrate plan
+  basic migrate apply`
+
+// migrationStep is one operation that takes the remote schema towards the
+// local one, e.g. {op: "change_type", path: "tables.todos.fields.done.type"}
+type migrationStep struct {
+	Op      string      `json:"op"`
+	Path    string      `json:"path"`
+	NewPath string      `json:"new_path,omitempty"`
+	From    interface{} `json:"from,omitempty"`
+	To      interface{} `json:"to,omitempty"`
+	// set for steps that lose or invalidate existing data
+	Warning string `json:"warning,omitempty"`
+}
+
+// migrationOrder runs additive steps before the ones that change or remove
+// what's there, so data is never dropped before its replacement exists
+var migrationOrder = map[string]int{
+	"add_table":      0,
+	"add_field":      1,
+	"rename_field":   2,
+	"change_setting": 3,
+	"change_table":   3,
+	"change_field":   3,
+	"change_type":    4,
+	"drop_field":     5,
+	"drop_table":     6,
+}
+
+// migrationPlan is what 'basic migrate plan --json' prints
+type migrationPlan struct {
+	ProjectID   string          `json:"project_id"`
+	FromVersion float64         `json:"from_version"`
+	Steps       []migrationStep `json:"steps"`
+	Destructive int             `json:"destructive"`
+
+	remote map[string]interface{}
+}
+
+// planMigration orders the changes from remoteSchema to localSchema into
+// migration steps
+func planMigration(remoteSchema, localSchema map[string]interface{}) []migrationStep {
+	changes := detectRenamedFields(filterIgnoredChanges(diffSchemas(remoteSchema, localSchema), schemaIgnorePaths()))
+
+	warnings := map[string]string{}
+	for _, c := range breakingChanges(remoteSchema, localSchema) {
+		warnings[c.Path] = c.Reason
+	}
+
+	steps := []migrationStep{}
+	for _, c := range changes {
+		step := migrationStep{Op: migrationOp(c), Path: c.Path, NewPath: c.NewPath, From: c.Old, To: c.New}
+		table, field := migrationTarget(c.Path)
+		switch step.Op {
+		case "drop_table":
+			step.Warning = fmt.Sprintf("drops table %s and all of its rows", table)
+		case "drop_field":
+			step.Warning = fmt.Sprintf("drops %s.%s and its value in every row", table, field)
+		case "change_type":
+			step.Warning = fmt.Sprintf("existing values of %s.%s may not convert from %s to %s", table, field, compactJSON(c.Old), compactJSON(c.New))
+		}
+		// the breaking change rules know more, like a field becoming required
+		if reason, ok := warnings[c.Path]; ok {
+			step.Warning = reason
+		}
+		steps = append(steps, step)
+	}
+
+	sort.SliceStable(steps, func(i, j int) bool {
+		if migrationOrder[steps[i].Op] != migrationOrder[steps[j].Op] {
+			return migrationOrder[steps[i].Op] < migrationOrder[steps[j].Op]
+		}
+		return steps[i].Path < steps[j].Path
+	})
+	return steps
+}
+
+func migrationOp(c schemaChange) string {
+	depth := len(strings.Split(c.Path, "."))
+	if c.Kind == "renamed" {
+		return "rename_field"
+	}
+	if _, ok := schemaPathTable(c.Path); !ok {
+		return "change_setting"
+	}
+	switch {
+	case depth == 2 && c.Kind == "added":
+		return "add_table"
+	case depth == 2 && c.Kind == "removed":
+		return "drop_table"
+	case depth == 4 && c.Kind == "added":
+		return "add_field"
+	case depth == 4 && c.Kind == "removed":
+		return "drop_field"
+	case depth == 5 && strings.HasSuffix(c.Path, ".type"):
+		return "change_type"
+	case depth >= 5:
+		return "change_field"
+	}
+	return "change_table"
+}
+
+// migrationTarget returns the table and field a path like
+// tables.todos.fields.done.type is about
+func migrationTarget(path string) (string, string) {
+	parts := strings.Split(path, ".")
+	table, field := "", ""
+	if len(parts) > 1 {
+		table = parts[1]
+	}
+	if len(parts) > 3 {
+		field = parts[3]
+	}
+	return table, field
+}
+
+// describe renders the step as a single line, e.g. "add field todos.due (string)"
+func (s migrationStep) describe() string {
+	table, field := migrationTarget(s.Path)
+	switch s.Op {
+	case "add_table":
+		tableData, _ := s.To.(map[string]interface{})
+		fields, _ := tableData["fields"].(map[string]interface{})
+		return fmt.Sprintf("add table %s (%s)", table, countNoun(len(fields), "field"))
+	case "drop_table":
+		return "drop table " + table
+	case "add_field":
+		fieldData, _ := s.To.(map[string]interface{})
+		fieldType, _ := fieldData["type"].(string)
+		return fmt.Sprintf("add field %s.%s (%s)", table, field, fieldType)
+	case "drop_field":
+		return fmt.Sprintf("drop field %s.%s", table, field)
+	case "rename_field":
+		_, newField := migrationTarget(s.NewPath)
+		return fmt.Sprintf("rename field %s.%s to %s", table, field, newField)
+	case "change_type":
+		return fmt.Sprintf("change type of %s.%s from %s to %s", table, field, compactJSON(s.From), compactJSON(s.To))
+	}
+	switch {
+	case s.From == nil:
+		return fmt.Sprintf("set %s to %s", s.Path, compactJSON(s.To))
+	case s.To == nil:
+		return fmt.Sprintf("remove %s", s.Path)
+	}
+	return fmt.Sprintf("change %s from %s to %s", s.Path, compactJSON(s.From), compactJSON(s.To))
+}
+
+// apply makes the step's change to schema. It fails when what the step
+// changes isn't there, like a field of a table whose step was skipped.
+func (s migrationStep) apply(schema map[string]interface{}) error {
+	parts := strings.Split(s.Path, ".")
+	parent := schema
+	for i, key := range parts[:len(parts)-1] {
+		child, ok := parent[key].(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("%s doesn't exist", strings.Join(parts[:i+1], "."))
+		}
+		parent = child
+	}
+	last := parts[len(parts)-1]
+
+	switch s.Op {
+	case "drop_table", "drop_field":
+		delete(parent, last)
+	case "rename_field":
+		_, newField := migrationTarget(s.NewPath)
+		if _, ok := parent[last]; !ok {
+			return fmt.Errorf("%s doesn't exist", s.Path)
+		}
+		if _, ok := parent[newField]; ok {
+			return fmt.Errorf("%s already exists", s.NewPath)
+		}
+		parent[newField] = parent[last]
+		delete(parent, last)
+	default:
+		if s.To == nil {
+			delete(parent, last)
+		} else {
+			parent[last] = s.To
+		}
+	}
+	return nil
+}
+
+// loadMigrationPlan plans the migration from the remote schema of the local
+// config's project to the local schema
+func loadMigrationPlan() (migrationPlan, map[string]interface{}, error) {
+	schema, err := readLocalSchema()
+	if err != nil {
+		return migrationPlan{}, nil, fmt.Errorf("error reading schema: %v", err)
+	}
+	localSchema, err := parseSchemaJSON(schema)
+	if err != nil {
+		return migrationPlan{}, nil, err
+	}
+	projectID, ok := localSchema["project_id"].(string)
+	if !ok || projectID == "" {
+		return migrationPlan{}, nil, fmt.Errorf("no project ID found in schema")
+	}
+
+	remote, err := getProjectSchema(projectID)
+	if err != nil {
+		return migrationPlan{}, nil, fmt.Errorf("error fetching remote schema: %v", err)
+	}
+	// a project without a schema starts from an empty one
+	remoteSchema := map[string]interface{}{"project_id": projectID, "version": float64(0), "tables": map[string]interface{}{}}
+	if remote != "" {
+		if remoteSchema, err = parseSchemaJSON(remote); err != nil {
+			return migrationPlan{}, nil, err
+		}
+	}
+	if _, ok := remoteSchema["tables"].(map[string]interface{}); !ok {
+		remoteSchema["tables"] = map[string]interface{}{}
+	}
+	fromVersion, _ := readSchemaVersion(remoteSchema)
+
+	plan := migrationPlan{ProjectID: projectID, FromVersion: fromVersion, Steps: planMigration(remoteSchema, localSchema), remote: remoteSchema}
+	for _, step := range plan.Steps {
+		if step.Warning != "" {
+			plan.Destructive++
+		}
+	}
+	return plan, localSchema, nil
+}
+
+// performMigrate implements 'basic migrate plan/apply'
+func performMigrate() tea.Msg {
+	args := positionalArgs()
+	if len(args) == 0 {
+		return usageMsg{text: migrateUsage}
+	}
+	switch args[0] {
+	case "plan":
+		plan, _, err := loadMigrationPlan()
+		if err != nil {
+			return errorScreenMsg{errorMessage: err.Error()}
+		}
+		printMigrationPlan(plan)
+		return tea.Quit()
+	case "apply":
+		return performMigrateApply()
+	}
+	return usageMsg{text: migrateUsage}
+}
+
+func printMigrationPlan(plan migrationPlan) {
+	if hasFlag("--json") {
+		out, _ := json.MarshalIndent(plan, "", "  ")
+		fmt.Println(string(out))
+		return
+	}
+
+	fmt.Printf("Project: %s\n", describeProject(plan.ProjectID, false))
+	if len(plan.Steps) == 0 {
+		fmt.Printf("Nothing to migrate, the local schema matches remote version %.0f.\n", plan.FromVersion)
+		return
+	}
+
+	fmt.Printf("Migration from remote version %.0f, in order:\n\n", plan.FromVersion)
+	warning := lipgloss.NewStyle().Foreground(lipgloss.Color("9"))
+	for i, step := range plan.Steps {
+		fmt.Printf("  %d. %s\n", i+1, step.describe())
+		if step.Warning != "" {
+			fmt.Println("     " + warning.Render("! "+step.Warning))
+		}
+	}
+	fmt.Println()
+	if plan.Destructive > 0 {
+		fmt.Println(warning.Render(fmt.Sprintf("%s can lose or invalidate existing data.", countNoun(plan.Destructive, "step"))))
+	}
+	fmt.Println("Run 'basic migrate apply' to push the steps one at a time.")
+}
+
+// migrationRun is the state of 'basic migrate apply' between steps
+type migrationRun struct {
+	plan migrationPlan
+	// the remote schema with the steps applied so far
+	schema  map[string]interface{}
+	version float64
+	applied int
+	skipped int
+}
+
+func performMigrateApply() tea.Msg {
+	plan, localSchema, err := loadMigrationPlan()
+	if err != nil {
+		return errorScreenMsg{errorMessage: err.Error()}
+	}
+	if len(plan.Steps) == 0 {
+		printMigrationPlan(plan)
+		return tea.Quit()
+	}
+	if !assumeYes() && !isInteractive() {
+		return errorScreenMsg{errorMessage: "migrate apply confirms every step, pass --yes to apply them all"}
+	}
+
+	// every step is pushed as a version of its own, so check the end result
+	// before pushing any of them
+	localJSON, _ := json.Marshal(localSchema)
+	validation, err := validateSchema(string(localJSON))
+	if err != nil {
+		return errorScreenMsg{errorMessage: fmt.Sprintf("error validating schema: %v", err)}
+	}
+	if result := newValidationResult(validation); !result.Valid {
+		return pushSchemaMsg{success: false, message: strings.Join(formatValidationResult(result), "\n"), exitCode: exitSchema}
+	}
+
+	printMigrationPlan(plan)
+	fmt.Println()
+	run := &migrationRun{plan: plan, schema: plan.remote, version: plan.FromVersion}
+	return run.next(0)
+}
+
+// next asks to apply step i, or finishes after the last step
+func (r *migrationRun) next(i int) tea.Msg {
+	if i == len(r.plan.Steps) {
+		return r.finish()
+	}
+	step := r.plan.Steps[i]
+	schema, reason, err := r.prepare(step)
+	if err != nil {
+		return pushSchemaMsg{success: false, message: fmt.Sprintf("Error applying step %d (%s): %v\n%s", i+1, step.describe(), err, r.progress())}
+	}
+	if reason != "" {
+		r.skipped++
+		fmt.Printf("Skipped step %d: %s, %s\n", i+1, step.describe(), reason)
+		return r.next(i + 1)
+	}
+
+	apply := func() tea.Msg {
+		if err := r.push(schema); err != nil {
+			return pushSchemaMsg{success: false, message: fmt.Sprintf("Error applying step %d (%s): %v\n%s", i+1, step.describe(), err, r.progress())}
+		}
+		fmt.Printf("Applied step %d: %s (version %.0f)\n", i+1, step.describe(), r.version)
+		return r.next(i + 1)
+	}
+	if assumeYes() {
+		return apply()
+	}
+
+	description := "Pushed as a new version of the remote schema."
+	if step.Warning != "" {
+		description = "Warning: " + step.Warning
+	}
+	return confirmMsg{
+		title:       fmt.Sprintf("Step %d of %d: %s?", i+1, len(r.plan.Steps), step.describe()),
+		description: description,
+		affirmative: "Yes, apply it",
+		run:         apply,
+		declined: func() tea.Msg {
+			r.skipped++
+			fmt.Printf("Skipped step %d: %s\n", i+1, step.describe())
+			return r.next(i + 1)
+		},
+	}
+}
+
+// prepare returns the schema with step applied as the next version, leaving
+// r.schema alone. The reason is set when the step can't be pushed: what it
+// changes is gone, or the schema would be invalid.
+func (r *migrationRun) prepare(step migrationStep) (map[string]interface{}, string, error) {
+	var schema map[string]interface{}
+	current, err := json.Marshal(r.schema)
+	if err != nil {
+		return nil, "", fmt.Errorf("error encoding schema: %v", err)
+	}
+	if err := json.Unmarshal(current, &schema); err != nil {
+		return nil, "", fmt.Errorf("error copying schema: %v", err)
+	}
+
+	if err := step.apply(schema); err != nil {
+		return nil, err.Error(), nil
+	}
+	schema["project_id"] = r.plan.ProjectID
+	schema["version"] = r.version + 1
+
+	encoded, err := json.Marshal(schema)
+	if err != nil {
+		return nil, "", fmt.Errorf("error encoding schema: %v", err)
+	}
+	validation, err := validateSchema(string(encoded))
+	if err != nil {
+		return nil, "", fmt.Errorf("error validating schema: %v", err)
+	}
+	if result := newValidationResult(validation); !result.Valid {
+		return nil, "the schema would be invalid:\n" + strings.Join(formatValidationResult(result)[1:], "\n"), nil
+	}
+	return schema, "", nil
+}
+
+// push publishes schema, a step applied by prepare, as the next version
+func (r *migrationRun) push(schema map[string]interface{}) error {
+	encoded, err := json.Marshal(schema)
+	if err != nil {
+		return fmt.Errorf("error encoding schema: %v", err)
+	}
+	if _, err := pushProjectSchema(string(encoded)); err != nil {
+		return err
+	}
+	r.schema = schema
+	r.version++
+	r.applied++
+	return nil
+}
+
+func (r *migrationRun) progress() string {
+	if r.applied == 0 {
+		return "Nothing was pushed."
+	}
+	return fmt.Sprintf("The %s before it are live as remote version %.0f.", countNoun(r.applied, "step"), r.version)
+}
+
+// finish points the local config at the new remote version: the same
+// version when every step was applied, the one after it when skipped steps
+// are still waiting to be pushed
+func (r *migrationRun) finish() tea.Msg {
+	if r.applied == 0 {
+		return pushSchemaMsg{success: true, message: "No steps applied, nothing was pushed."}
+	}
+
+	version := r.version
+	if r.skipped > 0 {
+		version++
+	}
+	lines := []string{fmt.Sprintf("Applied %d of %s, the remote schema is now version %.0f.", r.applied, countNoun(len(r.plan.Steps), "step"), r.version)}
+	if projectOverride() != "" {
+		// the local config belongs to another project
+		return pushSchemaMsg{success: true, message: strings.Join(lines, "\n")}
+	}
+	if err := setLocalSchemaVersion(version); err != nil {
+		lines = append(lines, fmt.Sprintf("Could not update the version in the local config: %v", err))
+	} else if r.skipped > 0 {
+		lines = append(lines, fmt.Sprintf("The %s you skipped stay in the local config as version %.0f, push them with 'basic push'.", countNoun(r.skipped, "step"), version))
+	}
+	return pushSchemaMsg{success: true, message: strings.Join(lines, "\n")}
+}
+
+// setLocalSchemaVersion sets the version of the schema in the local config,
+// leaving the rest of it alone
+func setLocalSchemaVersion(version float64) error {
+	schema, err := readSchemaFromConfig()
+	if err != nil {
+		return err
+	}
+	schemaData, err := parseSchemaJSON(schema)
+	if err != nil {
+		return err
+	}
+	schemaData["version"] = version
+	updated, err := json.MarshalIndent(schemaData, "\t", "\t")
+	if err != nil {
+		return fmt.Errorf("error formatting schema JSON: %v", err)
+	}
+	return saveSchemaToConfig(string(updated))
+}
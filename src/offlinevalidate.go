@@ -0,0 +1,371 @@
+package main
+
+import (
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// configJSONSchema describes a valid basic.config schema, for 'basic validate
+// --offline'. It only uses the JSON Schema keywords jsonSchemaNode supports.
+//
+//go:embed basic-config.schema.json
+var configJSONSchema []byte
+
+// jsonSchemaNode is the subset of JSON Schema needed to describe a basic schema
+type jsonSchemaNode struct {
+	Type       string                     `json:"type"`
+	Required   []string                   `json:"required"`
+	Properties map[string]*jsonSchemaNode `json:"properties"`
+	// false, or the schema every property not in Properties must match
+	AdditionalProperties json.RawMessage `json:"additionalProperties"`
+	PropertyNames        *jsonSchemaNode `json:"propertyNames"`
+	Pattern              string          `json:"pattern"`
+	Enum                 []interface{}   `json:"enum"`
+	Minimum              *float64        `json:"minimum"`
+	MinLength            *int            `json:"minLength"`
+	MinProperties        *int            `json:"minProperties"`
+}
+
+// offlineValidateSchema checks source, the schema JSON as written in its file,
+// against configJSONSchema without asking the API. firstLine is the line of the
+// file source starts on, so every error can point at its line.
+func offlineValidateSchema(source string, firstLine int) (validationResult, error) {
+	var root jsonSchemaNode
+	if err := json.Unmarshal(configJSONSchema, &root); err != nil {
+		return validationResult{}, fmt.Errorf("error reading the built-in config JSON Schema: %v", err)
+	}
+
+	result := validationResult{Valid: true, Errors: []validationResultError{}}
+	lines, duplicates, shadowed, err := schemaKeyLines(source, firstLine)
+	if err != nil {
+		var syntaxErr *json.SyntaxError
+		switch {
+		case errors.As(err, &syntaxErr):
+			line := firstLine + strings.Count(source[:syntaxErr.Offset], "\n")
+			result.Valid = false
+			result.Errors = append(result.Errors, validationResultError{Message: fmt.Sprintf("invalid JSON: %v", err), Line: line})
+			return result, nil
+		case errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF):
+			line := firstLine + strings.Count(source, "\n")
+			result.Valid = false
+			result.Errors = append(result.Errors, validationResultError{Message: "invalid JSON: unexpected end of schema, is a closing brace missing?", Line: line})
+			return result, nil
+		}
+		return validationResult{}, fmt.Errorf("error parsing schema: %v", err)
+	}
+	result.Errors = append(result.Errors, duplicates...)
+
+	var schemaData interface{}
+	if err := json.Unmarshal([]byte(source), &schemaData); err != nil {
+		return validationResult{}, fmt.Errorf("error parsing schema: %v", err)
+	}
+	root.validate("", schemaData, func(path, message string) {
+		result.Errors = append(result.Errors, validationResultError{Message: message, Path: path, Line: lineOfPath(lines, path, firstLine)})
+	})
+	// the server rejects a definition that's overridden by a later one with
+	// the same name if it's invalid, so check those too
+	for _, value := range shadowed {
+		node := root.nodeAt(value.path)
+		var data interface{}
+		if node == nil || json.Unmarshal([]byte(value.source), &data) != nil {
+			continue
+		}
+		valueLines, _, _, _ := schemaKeyLines(value.source, value.line)
+		node.validate(value.path, data, func(path, message string) {
+			relative := strings.TrimPrefix(strings.TrimPrefix(path, value.path), ".")
+			result.Errors = append(result.Errors, validationResultError{Message: message, Path: path, Line: lineOfPath(valueLines, relative, value.line)})
+		})
+	}
+
+	sort.SliceStable(result.Errors, func(i, j int) bool {
+		return result.Errors[i].Line < result.Errors[j].Line
+	})
+	result.Valid = len(result.Errors) == 0
+	return result, nil
+}
+
+// lineOfPath returns the line path's key is on, or its closest parent's
+func lineOfPath(lines map[string]int, path string, firstLine int) int {
+	for path != "" {
+		if line, ok := lines[path]; ok {
+			return line
+		}
+		cut := strings.LastIndex(path, ".")
+		if cut < 0 {
+			break
+		}
+		path = path[:cut]
+	}
+	return firstLine
+}
+
+// shadowedValue is a value whose key appears again later in the same object,
+// so parsing the schema drops it
+type shadowedValue struct {
+	path   string
+	source string
+	// the line source starts on
+	line int
+}
+
+// schemaKeyLines maps every path in source to the line its key is on. Keys
+// that appear twice in an object would silently overwrite each other once
+// parsed, so they're reported as errors, and the values they override are
+// returned to be validated on their own.
+func schemaKeyLines(source string, firstLine int) (map[string]int, []validationResultError, []shadowedValue, error) {
+	lines := map[string]int{}
+	var duplicates []validationResultError
+	var shadowed []shadowedValue
+	decoder := json.NewDecoder(strings.NewReader(source))
+	lineAt := func(offset int64) int {
+		return firstLine + strings.Count(source[:offset], "\n")
+	}
+
+	var walk func(path string) error
+	walk = func(path string) error {
+		token, err := decoder.Token()
+		if err != nil {
+			return err
+		}
+		delim, ok := token.(json.Delim)
+		if !ok {
+			return nil
+		}
+
+		switch delim {
+		case '{':
+			seen := map[string]shadowedValue{}
+			for decoder.More() {
+				token, err := decoder.Token()
+				if err != nil {
+					return err
+				}
+				key, _ := token.(string)
+				keyPath := joinSchemaPath(path, key)
+				start := decoder.InputOffset()
+				line := lineAt(start)
+				previous, isDuplicate := seen[key]
+				if isDuplicate {
+					duplicates = append(duplicates, validationResultError{Message: duplicateKeyMessage(keyPath, key), Path: keyPath, Line: line})
+					shadowed = append(shadowed, previous)
+				} else {
+					lines[keyPath] = line
+				}
+				if err := walk(keyPath); err != nil {
+					return err
+				}
+
+				// the value without the colon before it
+				value := source[start:decoder.InputOffset()]
+				trimmed := strings.TrimLeft(value, " \t\r\n:")
+				seen[key] = shadowedValue{path: keyPath, source: trimmed, line: lineAt(start + int64(len(value)-len(trimmed)))}
+			}
+		case '[':
+			for i := 0; decoder.More(); i++ {
+				if err := walk(joinSchemaPath(path, strconv.Itoa(i))); err != nil {
+					return err
+				}
+			}
+		}
+		// the closing delimiter
+		_, err = decoder.Token()
+		return err
+	}
+
+	if err := walk(""); err != nil {
+		return nil, nil, nil, err
+	}
+	return lines, duplicates, shadowed, nil
+}
+
+func duplicateKeyMessage(path, key string) string {
+	parts := strings.Split(path, ".")
+	switch {
+	case len(parts) == 2 && parts[0] == "tables":
+		return fmt.Sprintf("duplicate table name %q", key)
+	case len(parts) == 4 && parts[0] == "tables" && parts[2] == "fields":
+		return fmt.Sprintf("duplicate field %q in table %s", key, parts[1])
+	}
+	return fmt.Sprintf("duplicate key %q", key)
+}
+
+// validate reports every way value doesn't match the schema node
+func (n *jsonSchemaNode) validate(path string, value interface{}, report func(path, message string)) {
+	if len(n.Enum) > 0 {
+		for _, allowed := range n.Enum {
+			if reflect.DeepEqual(allowed, value) {
+				return
+			}
+		}
+		options := make([]string, len(n.Enum))
+		for i, allowed := range n.Enum {
+			options[i] = compactJSON(allowed)
+		}
+		report(path, fmt.Sprintf("must be one of %s, got %s", strings.Join(options, ", "), compactJSON(value)))
+		return
+	}
+
+	if n.Type != "" && jsonTypeOf(value) != n.Type && !(n.Type == "number" && jsonTypeOf(value) == "integer") {
+		report(path, fmt.Sprintf("must be %s, got %s", withArticle(n.Type), compactJSON(value)))
+		return
+	}
+
+	switch v := value.(type) {
+	case string:
+		if n.MinLength != nil && len(v) < *n.MinLength {
+			report(path, "must not be empty")
+		}
+	case float64:
+		if n.Minimum != nil && v < *n.Minimum {
+			report(path, fmt.Sprintf("must be at least %s, got %s", compactJSON(*n.Minimum), compactJSON(v)))
+		}
+	case map[string]interface{}:
+		n.validateObject(path, v, report)
+	}
+}
+
+func (n *jsonSchemaNode) validateObject(path string, object map[string]interface{}, report func(path, message string)) {
+	for _, key := range n.Required {
+		if _, ok := object[key]; !ok {
+			report(path, fmt.Sprintf("missing required property %q", key))
+		}
+	}
+	if n.MinProperties != nil && len(object) < *n.MinProperties {
+		report(path, fmt.Sprintf("must have at least %s", countNoun(*n.MinProperties, "entry")))
+	}
+
+	keys := make([]string, 0, len(object))
+	for key := range object {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	additional, additionalAllowed := n.additionalProperties()
+	for _, key := range keys {
+		keyPath := joinSchemaPath(path, key)
+		if n.PropertyNames != nil && n.PropertyNames.Pattern != "" {
+			if matched, _ := regexp.MatchString(n.PropertyNames.Pattern, key); !matched {
+				report(keyPath, fmt.Sprintf("%q is not a valid name: use letters, digits and underscores, not starting with a digit", key))
+			}
+		}
+
+		if property, ok := n.Properties[key]; ok {
+			property.validate(keyPath, object[key], report)
+		} else if additional != nil {
+			additional.validate(keyPath, object[key], report)
+		} else if !additionalAllowed {
+			report(keyPath, fmt.Sprintf("unknown property %q", key))
+		}
+	}
+}
+
+// nodeAt returns the schema node a value at path must match, or nil if
+// there's none
+func (n *jsonSchemaNode) nodeAt(path string) *jsonSchemaNode {
+	node := n
+	for _, key := range strings.Split(path, ".") {
+		if property, ok := node.Properties[key]; ok {
+			node = property
+			continue
+		}
+		additional, _ := node.additionalProperties()
+		if additional == nil {
+			return nil
+		}
+		node = additional
+	}
+	return node
+}
+
+// additionalProperties returns the schema for properties not listed in
+// Properties, or nil and whether they're allowed at all
+func (n *jsonSchemaNode) additionalProperties() (*jsonSchemaNode, bool) {
+	switch strings.TrimSpace(string(n.AdditionalProperties)) {
+	case "", "true":
+		return nil, true
+	case "false":
+		return nil, false
+	}
+	var node jsonSchemaNode
+	if err := json.Unmarshal(n.AdditionalProperties, &node); err != nil {
+		return nil, true
+	}
+	return &node, true
+}
+
+// jsonTypeOf names value's JSON Schema type
+func jsonTypeOf(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case string:
+		return "string"
+	case float64:
+		if v == float64(int64(v)) {
+			return "integer"
+		}
+		return "number"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	}
+	return "unknown"
+}
+
+func withArticle(noun string) string {
+	if strings.ContainsRune("aeiou", rune(noun[0])) {
+		return "an " + noun
+	}
+	return "a " + noun
+}
+
+// validateFileOffline is validateFile without the API: filename is read as
+// written so errors point at lines of the file
+func validateFileOffline(filename string) (validationResult, error) {
+	source, firstLine, err := schemaSourceFor(filename)
+	if err != nil {
+		return validationResult{}, fmt.Errorf("error reading schema: %v", err)
+	}
+
+	result, err := offlineValidateSchema(source, firstLine)
+	if err != nil {
+		return validationResult{}, err
+	}
+	if schemaData, err := parseSchemaJSON(source); err == nil {
+		result.Tables, result.Fields = countTablesAndFields(schemaData)
+		result.projectID, _ = schemaData["project_id"].(string)
+		result.version, _ = readSchemaVersion(schemaData)
+	}
+	return result, nil
+}
+
+// schemaSourceFor reads the schema like validateFile does, but as written
+func schemaSourceFor(filename string) (string, int, error) {
+	switch filename {
+	case "-":
+		content, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return "", 0, fmt.Errorf("error reading stdin: %v", err)
+		}
+		return string(content), 1, nil
+	case "":
+		configFile, err := schemaConfigFile()
+		if err != nil {
+			return "", 0, err
+		}
+		return readSchemaSource(configFile)
+	}
+	return readSchemaSource(filename)
+}
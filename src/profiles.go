@@ -0,0 +1,243 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/huh"
+)
+
+// defaultProfile keeps its token at ~/.basic-cli/token.json so logins from
+// before profiles existed keep working. Other profiles live in
+// ~/.basic-cli/profiles/<name>/token.json. Either file is only used when
+// there's no OS keychain to store the token in.
+const defaultProfile = "default"
+
+// cliSettings is stored in ~/.basic-cli/settings.json
+type cliSettings struct {
+	ActiveProfile string `json:"active_profile,omitempty"`
+	UpdateChannel string `json:"update_channel,omitempty"`
+}
+
+func getSettingsFilePath() (string, error) {
+	basicCliDir, err := getBasicCliDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(basicCliDir, settingsFileName), nil
+}
+
+func loadSettings() (cliSettings, error) {
+	var settings cliSettings
+
+	settingsFilePath, err := getSettingsFilePath()
+	if err != nil {
+		return settings, err
+	}
+
+	data, err := os.ReadFile(settingsFilePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return settings, nil
+		}
+		return settings, err
+	}
+
+	if err := json.Unmarshal(data, &settings); err != nil {
+		return cliSettings{}, fmt.Errorf("error parsing %s: %v", settingsFilePath, err)
+	}
+	return settings, nil
+}
+
+func saveSettings(settings cliSettings) error {
+	settingsFilePath, err := getSettingsFilePath()
+	if err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(settings, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(settingsFilePath), 0700); err != nil {
+		return err
+	}
+	return os.WriteFile(settingsFilePath, data, 0600)
+}
+
+// activeProfile returns the profile for this run: --profile, then the profile
+// chosen with 'basic profile use' or 'basic account switch', then the default
+// profile
+func activeProfile() string {
+	if profile := flagValue("--profile"); profile != "" {
+		return profile
+	}
+	if settings, err := loadSettings(); err == nil && settings.ActiveProfile != "" {
+		return settings.ActiveProfile
+	}
+	return defaultProfile
+}
+
+func getProfileDir(profile string) (string, error) {
+	basicCliDir, err := getBasicCliDir()
+	if err != nil {
+		return "", err
+	}
+	if profile == defaultProfile {
+		return basicCliDir, nil
+	}
+	if profile == "" || strings.ContainsAny(profile, `/\`) || profile == "." || profile == ".." {
+		return "", fmt.Errorf("invalid profile name %q", profile)
+	}
+	return filepath.Join(basicCliDir, profilesDirName, profile), nil
+}
+
+// listProfiles returns the default profile plus every profile that has been logged in to
+func listProfiles() ([]string, error) {
+	basicCliDir, err := getBasicCliDir()
+	if err != nil {
+		return nil, err
+	}
+
+	profiles := []string{}
+	entries, err := os.ReadDir(filepath.Join(basicCliDir, profilesDirName))
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	for _, entry := range entries {
+		if entry.IsDir() && entry.Name() != defaultProfile {
+			profiles = append(profiles, entry.Name())
+		}
+	}
+	sort.Strings(profiles)
+
+	return append([]string{defaultProfile}, profiles...), nil
+}
+
+// switchProfile makes profile the active profile for future commands
+func switchProfile(profile string) error {
+	profiles, err := listProfiles()
+	if err != nil {
+		return err
+	}
+
+	found := false
+	for _, p := range profiles {
+		if p == profile {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return fmt.Errorf("profile %q not found. log in to it first with 'basic login --profile %s'", profile, profile)
+	}
+
+	settings, err := loadSettings()
+	if err != nil {
+		return err
+	}
+	settings.ActiveProfile = profile
+	if profile == defaultProfile {
+		settings.ActiveProfile = ""
+	}
+	return saveSettings(settings)
+}
+
+// startAccountSwitch switches to the profile given as an argument, or asks
+// which profile to use
+func (m model) startAccountSwitch() (tea.Model, tea.Cmd) {
+	if args := positionalArgs(); len(args) > 1 {
+		return m, func() tea.Msg {
+			return performProfileSwitch(args[1])
+		}
+	}
+
+	if !isInteractive() {
+		return m, usage("Usage: basic account switch <profile>")
+	}
+
+	profiles, err := listProfiles()
+	if err != nil {
+		return m, func() tea.Msg {
+			return errorScreenMsg{errorMessage: err.Error()}
+		}
+	}
+
+	current := activeProfile()
+	options := []huh.Option[string]{}
+	for _, p := range profiles {
+		label := p
+		if p == current {
+			label += " (active)"
+		}
+		options = append(options, huh.NewOption(label, p))
+	}
+
+	m.switchingProfile = true
+	m.form = huh.NewForm(
+		huh.NewGroup(
+			huh.NewSelect[string]().
+				Key("profile").
+				Title("Which profile do you want to use?").
+				Options(options...).
+				Value(&current),
+		),
+	).WithShowHelp(false)
+	return m, m.form.Init()
+}
+
+func performProfileSwitch(profile string) tea.Msg {
+	if err := switchProfile(profile); err != nil {
+		return errorScreenMsg{errorMessage: err.Error()}
+	}
+	fmt.Printf("Switched to profile %s\n", profile)
+	return tea.Quit()
+}
+
+// performProfileList prints every profile and who it's logged in as, marking
+// the active one
+func performProfileList() tea.Msg {
+	profiles, err := listProfiles()
+	if err != nil {
+		return errorScreenMsg{errorMessage: err.Error()}
+	}
+	current := activeProfile()
+
+	if hasFlag("--json") {
+		type profileJSON struct {
+			Name   string `json:"name"`
+			Email  string `json:"email,omitempty"`
+			Active bool   `json:"active"`
+		}
+		out := []profileJSON{}
+		for _, p := range profiles {
+			account, _ := loadProfileAccountInfo(p)
+			out = append(out, profileJSON{Name: p, Email: account.Email, Active: p == current})
+		}
+		data, _ := json.MarshalIndent(out, "", "  ")
+		fmt.Println(string(data))
+		return tea.Quit()
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	for _, p := range profiles {
+		marker := " "
+		if p == current {
+			marker = "*"
+		}
+		email := "-"
+		if account, ok := loadProfileAccountInfo(p); ok && account.Email != "" {
+			email = account.Email
+		}
+		fmt.Fprintf(w, "%s %s\t%s\n", marker, p, email)
+	}
+	w.Flush()
+	return tea.Quit()
+}
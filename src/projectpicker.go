@@ -0,0 +1,370 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/huh"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// projectPickerHeight is how many projects the picker shows at once
+const projectPickerHeight = 10
+
+// projectPicker is a huh field that picks a project by typing part of its
+// name, slug or ID, matched fuzzily like fzf does. With dozens of projects
+// that's a lot quicker than scrolling a select.
+type projectPicker struct {
+	key      string
+	title    string
+	projects []project
+	value    *string
+
+	query   textinput.Model
+	matches []projectMatch
+	cursor  int
+	// first match shown
+	offset int
+
+	focused bool
+	err     error
+	theme   *huh.Theme
+	width   int
+}
+
+type projectMatch struct {
+	project project
+	score   int
+	// indexes of the runes of the name that matched, to highlight them
+	positions map[int]bool
+}
+
+var (
+	pickerUp     = key.NewBinding(key.WithKeys("up", "ctrl+p", "ctrl+k"), key.WithHelp("↑", "up"))
+	pickerDown   = key.NewBinding(key.WithKeys("down", "ctrl+n", "ctrl+j"), key.WithHelp("↓", "down"))
+	pickerSubmit = key.NewBinding(key.WithKeys("enter", "tab"), key.WithHelp("enter", "select"))
+	pickerPrev   = key.NewBinding(key.WithKeys("shift+tab"), key.WithHelp("shift+tab", "back"))
+)
+
+func newProjectPicker(key, title string, projects []project) *projectPicker {
+	query := textinput.New()
+	query.Prompt = "> "
+	query.Placeholder = "type to filter by name, slug or ID"
+
+	p := &projectPicker{key: key, title: title, projects: projects, value: new(string), query: query}
+	p.filter()
+	return p
+}
+
+// filter ranks the projects against the query, best match first. Every
+// space-separated word of the query has to match.
+func (p *projectPicker) filter() {
+	terms := strings.Fields(strings.ToLower(p.query.Value()))
+	p.matches = p.matches[:0]
+	for _, proj := range p.projects {
+		if match, ok := matchProject(proj, terms); ok {
+			p.matches = append(p.matches, match)
+		}
+	}
+	sort.SliceStable(p.matches, func(i, j int) bool {
+		return p.matches[i].score > p.matches[j].score
+	})
+	p.cursor = 0
+	p.offset = 0
+	p.updateValue()
+}
+
+func (p *projectPicker) updateValue() {
+	if p.cursor < len(p.matches) {
+		*p.value = p.matches[p.cursor].project.ID
+	} else {
+		*p.value = ""
+	}
+}
+
+// matchProject scores terms against the project's name, slug and ID,
+// keeping the best score of each term
+func matchProject(proj project, terms []string) (projectMatch, bool) {
+	match := projectMatch{project: proj, positions: map[int]bool{}}
+	name := []rune(strings.ToLower(proj.Name))
+	for _, term := range terms {
+		query := []rune(term)
+		best, found := 0, false
+		var bestPositions []int
+		for i, candidate := range [][]rune{name, []rune(generateSlugFromName(proj.Name)), []rune(strings.ToLower(proj.ID))} {
+			score, positions, ok := fuzzyMatch(query, candidate)
+			if i == 0 {
+				// a match in the name is what the user sees, so it wins ties
+				score += 2
+			}
+			if ok && (!found || score > best) {
+				best, found = score, true
+				bestPositions = nil
+				// only the name is shown, so only its matches are highlighted
+				if i == 0 {
+					bestPositions = positions
+				}
+			}
+		}
+		if !found {
+			return projectMatch{}, false
+		}
+		match.score += best
+		for _, pos := range bestPositions {
+			match.positions[pos] = true
+		}
+	}
+	return match, true
+}
+
+// fuzzyMatch reports whether query's runes appear in text in order, scoring
+// matches at word starts and runs of consecutive runes higher, and gaps lower
+func fuzzyMatch(query, text []rune) (int, []int, bool) {
+	if len(query) == 0 {
+		return 0, nil, true
+	}
+
+	best, found := 0, false
+	var bestPositions []int
+	// try every place the first rune matches, the first isn't always the best
+	for start := range text {
+		if text[start] != query[0] {
+			continue
+		}
+		score, positions := 0, []int{}
+		q := 0
+		for i := start; i < len(text) && q < len(query); i++ {
+			if text[i] != query[q] {
+				continue
+			}
+			score += 1
+			if i == 0 || !unicode.IsLetter(text[i-1]) && !unicode.IsDigit(text[i-1]) {
+				score += 8
+			}
+			if len(positions) > 0 {
+				if gap := i - positions[len(positions)-1] - 1; gap == 0 {
+					score += 5
+				} else {
+					score -= min(gap, 5)
+				}
+			}
+			positions = append(positions, i)
+			q++
+		}
+		if q == len(query) && (!found || score > best) {
+			best, bestPositions, found = score, positions, true
+		}
+	}
+	return best, bestPositions, found
+}
+
+func (p *projectPicker) Init() tea.Cmd {
+	return nil
+}
+
+func (p *projectPicker) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		switch {
+		case key.Matches(msg, pickerUp):
+			if p.cursor > 0 {
+				p.cursor--
+				p.offset = min(p.offset, p.cursor)
+				p.updateValue()
+			}
+			return p, nil
+		case key.Matches(msg, pickerDown):
+			if p.cursor < len(p.matches)-1 {
+				p.cursor++
+				if p.cursor >= p.offset+projectPickerHeight {
+					p.offset++
+				}
+				p.updateValue()
+			}
+			return p, nil
+		case key.Matches(msg, pickerPrev):
+			return p, huh.PrevField
+		case key.Matches(msg, pickerSubmit):
+			p.err = p.validate()
+			if p.err != nil {
+				return p, nil
+			}
+			return p, huh.NextField
+		}
+	}
+
+	previous := p.query.Value()
+	var cmd tea.Cmd
+	p.query, cmd = p.query.Update(msg)
+	if p.query.Value() != previous {
+		p.err = nil
+		p.filter()
+	}
+	return p, cmd
+}
+
+func (p *projectPicker) validate() error {
+	if len(p.matches) == 0 {
+		return fmt.Errorf("no project matches %q", p.query.Value())
+	}
+	return nil
+}
+
+func (p *projectPicker) styles() *huh.FieldStyles {
+	theme := p.theme
+	if theme == nil {
+		theme = huh.ThemeCharm()
+	}
+	if p.focused {
+		return &theme.Focused
+	}
+	return &theme.Blurred
+}
+
+func (p *projectPicker) View() string {
+	styles := p.styles()
+
+	var s strings.Builder
+	s.WriteString(styles.Title.Render(p.title))
+	if p.err != nil {
+		s.WriteString(styles.ErrorIndicator.String())
+	}
+	s.WriteString("\n")
+
+	if !p.focused {
+		if p.cursor < len(p.matches) {
+			s.WriteString(styles.SelectedOption.Render(p.matches[p.cursor].project.Name))
+		}
+		return styles.Base.Render(s.String())
+	}
+
+	s.WriteString(p.query.View() + "\n")
+	selector := styles.SelectSelector.String()
+	muted := lipgloss.NewStyle().Foreground(lipgloss.Color("240"))
+	for i := p.offset; i < len(p.matches) && i < p.offset+projectPickerHeight; i++ {
+		match := p.matches[i]
+		style := styles.UnselectedOption
+		prefix := strings.Repeat(" ", lipgloss.Width(selector))
+		if i == p.cursor {
+			style = styles.SelectedOption
+			prefix = selector
+		}
+		id := match.project.ID
+		if p.width > 0 {
+			// keep each project on one line, the ID is the least readable part
+			room := p.width - lipgloss.Width(selector) - lipgloss.Width(match.project.Name) - 1
+			id = truncateRunes(id, room)
+		}
+		s.WriteString(prefix + highlightMatch(match, style) + " " + muted.Render(id) + "\n")
+	}
+	if len(p.matches) == 0 {
+		s.WriteString(muted.Render("  no matching projects") + "\n")
+	}
+	s.WriteString(muted.Render(fmt.Sprintf("  %d/%d projects", len(p.matches), len(p.projects))))
+	if p.err != nil {
+		s.WriteString("\n" + styles.ErrorMessage.Render(p.err.Error()))
+	}
+	return styles.Base.Render(s.String())
+}
+
+// highlightMatch renders the project's name with the matched runes underlined
+func highlightMatch(match projectMatch, style lipgloss.Style) string {
+	if len(match.positions) == 0 {
+		return style.Render(match.project.Name)
+	}
+	highlight := style.Underline(true).Bold(true)
+	var s strings.Builder
+	for i, r := range []rune(match.project.Name) {
+		if match.positions[i] {
+			s.WriteString(highlight.Render(string(r)))
+		} else {
+			s.WriteString(style.Render(string(r)))
+		}
+	}
+	return s.String()
+}
+
+func (p *projectPicker) Focus() tea.Cmd {
+	p.focused = true
+	return p.query.Focus()
+}
+
+func (p *projectPicker) Blur() tea.Cmd {
+	p.focused = false
+	p.query.Blur()
+	p.err = p.validate()
+	return nil
+}
+
+func (p *projectPicker) Error() error {
+	return p.err
+}
+
+func (p *projectPicker) Run() error {
+	return huh.NewForm(huh.NewGroup(p)).WithShowHelp(false).Run()
+}
+
+func (p *projectPicker) Skip() bool {
+	return false
+}
+
+func (p *projectPicker) Zoom() bool {
+	return false
+}
+
+func (p *projectPicker) KeyBinds() []key.Binding {
+	return []key.Binding{pickerUp, pickerDown, pickerSubmit, pickerPrev}
+}
+
+func (p *projectPicker) WithTheme(theme *huh.Theme) huh.Field {
+	if p.theme == nil {
+		p.theme = theme
+	}
+	return p
+}
+
+func (p *projectPicker) WithAccessible(bool) huh.Field {
+	return p
+}
+
+func (p *projectPicker) WithKeyMap(*huh.KeyMap) huh.Field {
+	return p
+}
+
+func (p *projectPicker) WithWidth(width int) huh.Field {
+	p.width = width
+	p.query.Width = max(width-4, 0)
+	return p
+}
+
+func (p *projectPicker) WithHeight(int) huh.Field {
+	return p
+}
+
+func (p *projectPicker) WithPosition(huh.FieldPosition) huh.Field {
+	return p
+}
+
+func (p *projectPicker) GetKey() string {
+	return p.key
+}
+
+func (p *projectPicker) GetValue() any {
+	return *p.value
+}
+
+// truncateRunes cuts s to n runes, ending in an ellipsis when it's cut
+func truncateRunes(s string, n int) string {
+	runes := []rune(s)
+	switch {
+	case len(runes) <= n:
+		return s
+	case n <= 1:
+		return ""
+	}
+	return string(runes[:n-1]) + "…"
+}
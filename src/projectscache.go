@@ -0,0 +1,67 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// projectsCache is the last successful project listing, shown by
+// 'basic projects' when we're offline
+type projectsCache struct {
+	FetchedAt time.Time `json:"fetched_at"`
+	Projects  []project `json:"projects"`
+}
+
+// projects are cached per profile since each profile sees different projects
+func getProjectsCacheFilePath() (string, error) {
+	profileDir, err := getProfileDir(activeProfile())
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(profileDir, projectsCacheFileName), nil
+}
+
+// saveProjectsCache is best effort; a missing cache only means no offline fallback
+func saveProjectsCache(projects []project) error {
+	cacheFilePath, err := getProjectsCacheFilePath()
+	if err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(projectsCache{FetchedAt: time.Now(), Projects: projects})
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(cacheFilePath), 0700); err != nil {
+		return err
+	}
+	return os.WriteFile(cacheFilePath, data, 0600)
+}
+
+// loadProjectsCache returns the cached projects, or false if there's no cache
+func loadProjectsCache() (projectsCache, bool) {
+	var cache projectsCache
+
+	cacheFilePath, err := getProjectsCacheFilePath()
+	if err != nil {
+		return cache, false
+	}
+
+	data, err := os.ReadFile(cacheFilePath)
+	if err != nil {
+		return cache, false
+	}
+	if err := json.Unmarshal(data, &cache); err != nil {
+		return projectsCache{}, false
+	}
+	return cache, true
+}
+
+// cachedProjectsBanner warns that the listing came from the cache
+func cachedProjectsBanner(fetchedAt time.Time) string {
+	return fmt.Sprintf("You are offline - showing cached projects from %s, possibly stale", fetchedAt.Local().Format("2006-01-02 15:04"))
+}
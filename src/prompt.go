@@ -0,0 +1,159 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// remote versions younger than this are reused by 'basic status --prompt'
+// instead of asking the API again
+const promptCacheTTL = time.Minute
+
+// promptFetchBudget is how long 'basic status --prompt' waits for the API, so
+// a slow or offline network doesn't hold up the shell prompt
+const promptFetchBudget = 300 * time.Millisecond
+
+// remoteSchemaCacheEntry is the last remote schema seen for a project
+type remoteSchemaCacheEntry struct {
+	Schema    string    `json:"schema"`
+	FetchedAt time.Time `json:"fetched_at"`
+}
+
+// cached per profile, as each profile may see different projects
+func getRemoteSchemaCacheFilePath() (string, error) {
+	profileDir, err := getProfileDir(activeProfile())
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(profileDir, remoteSchemaCacheFileName), nil
+}
+
+func loadRemoteSchemaCache() map[string]remoteSchemaCacheEntry {
+	cache := map[string]remoteSchemaCacheEntry{}
+
+	cacheFilePath, err := getRemoteSchemaCacheFilePath()
+	if err != nil {
+		return cache
+	}
+	data, err := os.ReadFile(cacheFilePath)
+	if err != nil {
+		return cache
+	}
+	if err := json.Unmarshal(data, &cache); err != nil {
+		return map[string]remoteSchemaCacheEntry{}
+	}
+	return cache
+}
+
+func saveRemoteSchemaCache(cache map[string]remoteSchemaCacheEntry) error {
+	cacheFilePath, err := getRemoteSchemaCacheFilePath()
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(cache)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(cacheFilePath), 0700); err != nil {
+		return err
+	}
+	return os.WriteFile(cacheFilePath, data, 0600)
+}
+
+// cachedRemoteSchema returns the project's remote schema, from the cache if
+// it's fresh. If the API can't be reached within promptFetchBudget a stale
+// cached schema is used.
+func cachedRemoteSchema(projectID string) (string, error) {
+	cache := loadRemoteSchemaCache()
+	entry, cached := cache[projectID]
+	if cached && time.Since(entry.FetchedAt) < promptCacheTTL {
+		return entry.Schema, nil
+	}
+
+	type result struct {
+		schema string
+		err    error
+	}
+	// buffered so the fetch can finish after we've stopped waiting for it
+	results := make(chan result, 1)
+	go func() {
+		schema, err := getProjectSchema(projectID)
+		results <- result{schema: schema, err: err}
+	}()
+
+	var r result
+	select {
+	case r = <-results:
+	case <-time.After(promptFetchBudget):
+		r.err = fmt.Errorf("the API didn't answer within %s", promptFetchBudget)
+	}
+	if r.err != nil {
+		if cached {
+			return entry.Schema, nil
+		}
+		return "", r.err
+	}
+
+	cacheRemoteSchema(projectID, r.schema)
+	return r.schema, nil
+}
+
+// cacheRemoteSchema remembers the remote schema for 'status --prompt' and
+// 'status --no-fetch'. Failing to save it is never fatal.
+func cacheRemoteSchema(projectID, schema string) {
+	cache := loadRemoteSchemaCache()
+	cache[projectID] = remoteSchemaCacheEntry{Schema: schema, FetchedAt: time.Now()}
+	saveRemoteSchemaCache(cache)
+}
+
+// statusPrompt returns a compact status for shell prompts, e.g. "basic:v3↑".
+// It returns "" outside of a basic project so prompts stay clean.
+func statusPrompt() string {
+	schema, err := readSchemaFromConfig()
+	if err != nil || schema == "" {
+		return ""
+	}
+	localSchema, err := parseSchemaJSON(schema)
+	if err != nil {
+		return "basic:?"
+	}
+	projectID, _ := localSchema["project_id"].(string)
+	localVersion, err := readSchemaVersion(localSchema)
+	if projectID == "" || err != nil {
+		return "basic:?"
+	}
+
+	prefix := fmt.Sprintf("basic:v%.0f", localVersion)
+
+	remote, err := cachedRemoteSchema(projectID)
+	if err != nil {
+		return prefix + "?"
+	}
+
+	// no published schema yet is the same as remote version 0
+	remoteSchema := map[string]interface{}{"version": float64(0)}
+	if remote != "" {
+		if remoteSchema, err = parseSchemaJSON(remote); err != nil {
+			return prefix + "?"
+		}
+	}
+	remoteVersion, _ := readSchemaVersion(remoteSchema)
+
+	switch {
+	case localVersion > remoteVersion:
+		return prefix + "↑"
+	case localVersion < remoteVersion:
+		return prefix + "↓"
+	}
+
+	if remote == "" {
+		return prefix + "="
+	}
+	if changes := filterIgnoredChanges(diffSchemas(remoteSchema, localSchema), schemaIgnorePaths()); len(changes) > 0 {
+		return prefix + "!"
+	}
+	return prefix + "="
+}
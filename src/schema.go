@@ -0,0 +1,466 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// ----------------------------- //
+//   📐 SCHEMA COMMANDS          //
+// ----------------------------- //
+
+const schemaUsage = `Usage: basic schema <command>
+
+Commands:
+  watch - Validate schema on every config save (--push to also push)
+  validate - Validate the local schema (same as 'basic validate', --against-remote to catch breaking changes)
+  unused - List tables that look unused and could be cleaned up
+  import <file> - Replace the config's schema with a JSON schema file
+  describe <table> - Show a table's fields, types and constraints
+  copy <from> <to> - Copy one project's schema to another project
+  export - Print the schema as JSON Schema (--format jsonschema|openapi, --out <file>)
+  init-remote - Publish the local schema as the first version of a project with no schema yet
+  history - List published schema versions and restore one
+  rollback <version> - Restore a published version to the local config (--push to also publish it)
+`
+
+func (m model) runSchemaCommand() (tea.Model, tea.Cmd) {
+	args := positionalArgs()
+	subcommand := ""
+	if len(args) > 0 {
+		subcommand = args[0]
+	}
+
+	switch subcommand {
+	case "watch":
+		token, err := loadToken()
+		if err != nil || token == nil {
+			return m, func() tea.Msg {
+				return errorScreenMsg{errorMessage: loggedOutError(err)}
+			}
+		}
+
+		filename, err := findConfigFile()
+		if err != nil {
+			return m, func() tea.Msg {
+				return errorScreenMsg{errorMessage: err.Error()}
+			}
+		}
+
+		push := hasFlag("--push")
+		m.state = stateWatching
+		m.watchHeader = fmt.Sprintf("Watching %s for changes...", filename)
+		if push {
+			m.watchHeader += " (valid schemas will be pushed)"
+		}
+		return m, runWatchCheck(filename, schemaWatchCheck(push), false)
+	case "validate":
+		if hasFlag("--watch") {
+			return m.startValidateWatch()
+		}
+		return m, performValidate
+	case "unused":
+		return m, performSchemaUnused
+	case "import":
+		if len(args) < 2 {
+			return m, usage("Usage: basic schema import <file.json>")
+		}
+		return m, func() tea.Msg {
+			return performSchemaImport(args[1])
+		}
+	case "describe":
+		if len(args) < 2 {
+			return m, usage("Usage: basic schema describe <table>")
+		}
+		return m, func() tea.Msg {
+			return performSchemaDescribe(args[1])
+		}
+	case "copy":
+		if len(args) < 3 {
+			return m, usage("Usage: basic schema copy <fromProjectID> <toProjectID>")
+		}
+		m.showMessages = true
+		return m, func() tea.Msg {
+			return performSchemaCopy(args[1], args[2])
+		}
+	case "export":
+		return m, performSchemaExport
+	case "init-remote":
+		m.showMessages = true
+		return m, performSchemaInitRemote
+	case "history":
+		if !isInteractive() {
+			return m, performHistory
+		}
+		return m, loadSchemaHistory
+	case "rollback":
+		if len(args) < 2 {
+			return m, usage("Usage: basic schema rollback <version> [--push]")
+		}
+		m.showMessages = true
+		return m, func() tea.Msg {
+			return performSchemaRollback(args[1])
+		}
+	default:
+		return m, usage(strings.TrimSuffix(schemaUsage, "\n"))
+	}
+}
+
+// performSchemaUnused lists tables in the local schema that define no fields.
+// The API doesn't expose row counts, so empty tables can't be detected yet.
+func performSchemaUnused() tea.Msg {
+	schema, err := readSchemaFromConfig()
+	if err != nil {
+		return errorScreenMsg{errorMessage: fmt.Sprintf("error reading schema: %v", err)}
+	}
+
+	schemaData, err := parseSchemaJSON(schema)
+	if err != nil {
+		return errorScreenMsg{errorMessage: err.Error(), code: "schema"}
+	}
+
+	tables, _ := schemaData["tables"].(map[string]interface{})
+	var unused []string
+	for name, table := range tables {
+		tableData, _ := table.(map[string]interface{})
+		fields, _ := tableData["fields"].(map[string]interface{})
+		if len(fields) == 0 {
+			unused = append(unused, name)
+		}
+	}
+	sort.Strings(unused)
+
+	if len(unused) == 0 {
+		fmt.Printf("All %d tables define at least one field.\n", len(tables))
+		return tea.Quit()
+	}
+
+	fmt.Println("Tables with no fields defined (candidates for cleanup):")
+	for _, name := range unused {
+		fmt.Printf(" - %s\n", name)
+	}
+	return tea.Quit()
+}
+
+// performSchemaImport validates a plain JSON schema and writes it into the
+// local config's schema block, keeping the config's project_id and name
+func performSchemaImport(filename string) tea.Msg {
+	configFile, err := findConfigFile()
+	if err != nil {
+		return errorScreenMsg{errorMessage: err.Error()}
+	}
+
+	projectID, err := getLocalProjectID()
+	if err != nil {
+		return errorScreenMsg{errorMessage: fmt.Sprintf("error reading project from %s: %v", configFile, err)}
+	}
+
+	content, err := os.ReadFile(filename)
+	if err != nil {
+		return errorScreenMsg{errorMessage: fmt.Sprintf("error reading %s: %v", filename, err)}
+	}
+
+	imported, err := parseSchemaJSON(string(content))
+	if err != nil {
+		return errorScreenMsg{errorMessage: fmt.Sprintf("error parsing %s: %v", filename, err), code: "schema"}
+	}
+
+	if importedID, ok := imported["project_id"].(string); ok && importedID != "" && importedID != projectID {
+		return errorScreenMsg{errorMessage: fmt.Sprintf("%s is for project %s, but %s is for project %s", filename, importedID, configFile, projectID), code: "usage"}
+	}
+	imported["project_id"] = projectID
+	// the name belongs to the config too, not to the imported file
+	delete(imported, "name")
+	if current, err := readSchemaFromConfig(); err == nil {
+		if currentData, err := parseSchemaJSON(current); err == nil {
+			if name, ok := currentData["name"]; ok {
+				imported["name"] = name
+			}
+		}
+	}
+
+	schema, err := json.MarshalIndent(imported, "\t", "\t")
+	if err != nil {
+		return errorScreenMsg{errorMessage: fmt.Sprintf("error formatting schema JSON: %v", err)}
+	}
+
+	validation, err := validateSchema(string(schema))
+	if err != nil {
+		return errorScreenMsg{errorMessage: fmt.Sprintf("error validating schema: %v", err)}
+	}
+	if result := newValidationResult(validation); !result.Valid {
+		lines := []string{fmt.Sprintf("Errors found in %s! Please fix:", filename)}
+		for _, e := range result.Errors {
+			lines = append(lines, fmt.Sprintf(" - %s", e.Message))
+		}
+		return errorScreenMsg{errorMessage: strings.Join(lines, "\n"), code: "schema"}
+	}
+
+	if err := saveSchemaToConfig(string(schema)); err != nil {
+		return errorScreenMsg{errorMessage: fmt.Sprintf("error saving schema to config: %v", err)}
+	}
+
+	fmt.Printf("Imported schema from %s into %s\n", filename, configFile)
+	return tea.Quit()
+}
+
+// performSchemaDescribe prints a table's fields from the local schema and
+// whether the table exists in the remote schema
+func performSchemaDescribe(tableName string) tea.Msg {
+	schema, err := readSchemaFromConfig()
+	if err != nil {
+		return errorScreenMsg{errorMessage: fmt.Sprintf("error reading schema: %v", err)}
+	}
+
+	schemaData, err := parseSchemaJSON(schema)
+	if err != nil {
+		return errorScreenMsg{errorMessage: err.Error(), code: "schema"}
+	}
+
+	tables, _ := schemaData["tables"].(map[string]interface{})
+	table, ok := tables[tableName].(map[string]interface{})
+	if !ok {
+		names := make([]string, 0, len(tables))
+		for name := range tables {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		return errorScreenMsg{errorMessage: fmt.Sprintf("table %s not found in the local schema, tables: %s", tableName, strings.Join(names, ", "))}
+	}
+
+	header := "Table " + tableName
+	if tableType, ok := table["type"].(string); ok {
+		header += fmt.Sprintf(" (%s)", tableType)
+	}
+	fmt.Println(header)
+	fmt.Println()
+
+	fields, _ := table["fields"].(map[string]interface{})
+	if len(fields) == 0 {
+		fmt.Println("No fields defined")
+	} else {
+		fieldNames := make([]string, 0, len(fields))
+		for name := range fields {
+			fieldNames = append(fieldNames, name)
+		}
+		sort.Strings(fieldNames)
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "FIELD\tTYPE\tCONSTRAINTS")
+		for _, name := range fieldNames {
+			field, _ := fields[name].(map[string]interface{})
+			fieldType, _ := field["type"].(string)
+			fmt.Fprintf(w, "%s\t%s\t%s\n", name, fieldType, fieldConstraints(field))
+		}
+		w.Flush()
+	}
+
+	fmt.Println()
+	projectID, _ := schemaData["project_id"].(string)
+	remote, err := getProjectSchema(projectID)
+	switch {
+	case err != nil:
+		fmt.Printf("Could not check the remote schema: %v\n", err)
+	case remote == "":
+		fmt.Println("Remote: no schema published yet")
+	default:
+		remoteSchema, err := parseSchemaJSON(remote)
+		if err != nil {
+			fmt.Printf("Could not check the remote schema: %v\n", err)
+			break
+		}
+		remoteTables, _ := remoteSchema["tables"].(map[string]interface{})
+		if _, ok := remoteTables[tableName]; ok {
+			fmt.Println("Remote: table exists")
+		} else {
+			fmt.Println("Remote: table not published yet - run 'basic push' to publish it")
+		}
+	}
+
+	return tea.Quit()
+}
+
+// fieldConstraints lists everything about a field other than its type,
+// e.g. "indexed, required"
+func fieldConstraints(field map[string]interface{}) string {
+	keys := make([]string, 0, len(field))
+	for key := range field {
+		if key != "type" {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	constraints := []string{}
+	for _, key := range keys {
+		switch value := field[key].(type) {
+		case bool:
+			if value {
+				constraints = append(constraints, key)
+			}
+		default:
+			constraints = append(constraints, fmt.Sprintf("%s=%s", key, compactJSON(value)))
+		}
+	}
+	if len(constraints) == 0 {
+		return "-"
+	}
+	return strings.Join(constraints, ", ")
+}
+
+// performSchemaCopy publishes the schema of project from to project to, as
+// the next version of to's schema. Since this replaces to's schema it asks
+// for confirmation, or needs --yes when there's no terminal.
+func performSchemaCopy(from, to string) tea.Msg {
+	token, err := loadToken()
+	if err != nil || token == nil {
+		return errorScreenMsg{errorMessage: loggedOutError(err)}
+	}
+	if err := checkProjectAccess(token, to); err != nil {
+		return errorScreenMsg{errorMessage: err.Error()}
+	}
+
+	source, err := getProjectSchema(from)
+	if err != nil {
+		return errorScreenMsg{errorMessage: fmt.Sprintf("error fetching schema of %s: %v", from, err)}
+	}
+	if source == "" {
+		return errorScreenMsg{errorMessage: fmt.Sprintf("project %s has no schema to copy", from)}
+	}
+	schemaData, err := parseSchemaJSON(source)
+	if err != nil {
+		return errorScreenMsg{errorMessage: err.Error()}
+	}
+
+	// the copy has to be a newer version than what the target has now
+	var targetVersion float64
+	target, err := getProjectSchema(to)
+	if err != nil {
+		return errorScreenMsg{errorMessage: fmt.Sprintf("error fetching schema of %s: %v", to, err)}
+	}
+	if target != "" {
+		if targetData, err := parseSchemaJSON(target); err == nil {
+			targetVersion, _ = readSchemaVersion(targetData)
+		}
+	}
+
+	schemaData["project_id"] = to
+	schemaData["version"] = targetVersion + 1
+
+	schema, err := json.MarshalIndent(schemaData, "\t", "\t")
+	if err != nil {
+		return errorScreenMsg{errorMessage: fmt.Sprintf("error formatting schema JSON: %v", err)}
+	}
+
+	validation, err := validateSchema(string(schema))
+	if err != nil {
+		return errorScreenMsg{errorMessage: fmt.Sprintf("error validating schema: %v", err)}
+	}
+	if result := newValidationResult(validation); !result.Valid {
+		lines := []string{fmt.Sprintf("The schema of %s can't be published to %s:", from, to)}
+		for _, e := range result.Errors {
+			lines = append(lines, fmt.Sprintf(" - %s", e.Message))
+		}
+		return pushSchemaMsg{success: false, message: strings.Join(lines, "\n")}
+	}
+
+	push := func() tea.Msg {
+		if _, err := pushProjectSchema(string(schema)); err != nil {
+			return pushSchemaMsg{success: false, message: fmt.Sprintf("Error pushing schema: %v", err)}
+		}
+		return pushSchemaMsg{success: true, message: fmt.Sprintf("Copied schema from %s to %s (now version %.0f)", from, to, targetVersion+1)}
+	}
+
+	if assumeYes() {
+		return push()
+	}
+	if !isInteractive() {
+		return errorScreenMsg{errorMessage: "schema copy replaces the target project's schema, pass --yes to confirm"}
+	}
+
+	tables, _ := schemaData["tables"].(map[string]interface{})
+	return confirmMsg{
+		title: fmt.Sprintf("Copy the schema of %s to %s?", from, to),
+		description: fmt.Sprintf("%d tables will be published to %s as version %.0f, replacing its current schema.",
+			len(tables), to, targetVersion+1),
+		affirmative: "Yes, copy schema",
+		run:         push,
+		cancelled:   "Schema copy cancelled",
+	}
+}
+
+// performSchemaInitRemote publishes the local schema to a project whose remote
+// schema is still empty, which 'basic push' can't do as it only pushes
+// schemas that are ahead of the remote one
+func performSchemaInitRemote() tea.Msg {
+	token, err := loadToken()
+	if err != nil || token == nil {
+		return errorScreenMsg{errorMessage: loggedOutError(err)}
+	}
+
+	schema, err := readSchemaFromConfig()
+	if err != nil {
+		return errorScreenMsg{errorMessage: fmt.Sprintf("error reading schema: %v", err)}
+	}
+	schemaData, err := parseSchemaJSON(schema)
+	if err != nil {
+		return errorScreenMsg{errorMessage: err.Error()}
+	}
+	projectID, _ := schemaData["project_id"].(string)
+	if projectID == "" {
+		return errorScreenMsg{errorMessage: "project_id not found in schema"}
+	}
+
+	remote, err := getProjectSchema(projectID)
+	if err != nil {
+		return errorScreenMsg{errorMessage: fmt.Sprintf("error fetching remote schema: %v", err)}
+	}
+	if remote != "" {
+		if remoteData, err := parseSchemaJSON(remote); err == nil {
+			remoteVersion, _ := readSchemaVersion(remoteData)
+			remoteTables, _ := remoteData["tables"].(map[string]interface{})
+			if remoteVersion > 0 || len(remoteTables) > 0 {
+				return pushSchemaMsg{success: false, message: fmt.Sprintf(
+					"Project %s already has a schema (version %.0f). Use 'basic push' to publish changes to it.", projectID, remoteVersion)}
+			}
+		}
+	}
+
+	// the first published schema is version 1
+	bumped := false
+	if version, _ := readSchemaVersion(schemaData); version < 1 {
+		bumped = true
+		schemaData["version"] = 1
+		formatted, err := json.MarshalIndent(schemaData, "\t", "\t")
+		if err != nil {
+			return errorScreenMsg{errorMessage: fmt.Sprintf("error formatting schema JSON: %v", err)}
+		}
+		schema = string(formatted)
+	}
+
+	validation, err := validateSchema(schema)
+	if err != nil {
+		return errorScreenMsg{errorMessage: fmt.Sprintf("error validating schema: %v", err)}
+	}
+	if result := newValidationResult(validation); !result.Valid {
+		return pushSchemaMsg{success: false, message: strings.Join(formatValidationResult(result), "\n"), exitCode: exitSchema}
+	}
+
+	if _, err := pushProjectSchema(schema); err != nil {
+		return pushSchemaMsg{success: false, message: fmt.Sprintf("Error pushing schema: %v", err)}
+	}
+	if !bumped {
+		return pushSchemaMsg{success: true, message: fmt.Sprintf("Published the local schema as the first version of %s", projectID)}
+	}
+	if err := saveSchemaToConfig(schema); err != nil {
+		return pushSchemaMsg{success: true, message: fmt.Sprintf(
+			"Published the first schema of %s, but could not update the local config to version 1: %v", projectID, err)}
+	}
+	return pushSchemaMsg{success: true, message: fmt.Sprintf("Published the local schema as version 1 of %s and updated the local config", projectID)}
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// inConfigDir runs the test in a temporary directory holding a JSON config
+func inConfigDir(t *testing.T) {
+	t.Helper()
+	dir := t.TempDir()
+	t.Setenv("HOME", dir)
+	config := `{"config": {"name": "test", "project_id": "p1"}, "schema": {"project_id": "p1", "version": 1, "tables": {"todos": {"type": "collection", "fields": {"title": {"type": "string"}}}}}}`
+	if err := os.WriteFile(filepath.Join(dir, "basic.config.json"), []byte(config), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.Chdir(wd) })
+}
+
+func TestFailingSchemaCommandsExitWithError(t *testing.T) {
+	inConfigDir(t)
+
+	tests := []struct {
+		name string
+		args []string
+		run  func() tea.Msg
+	}{
+		{"import of a missing file", []string{"import", "missing.json"}, func() tea.Msg { return performSchemaImport("missing.json") }},
+		{"describe of a missing table", []string{"describe", "nosuchtable"}, func() tea.Msg { return performSchemaDescribe("nosuchtable") }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cliArgs = tt.args
+			updated, _ := initialModel("schema").Update(tt.run())
+			if got := updated.(model).exitCode; got != exitError {
+				t.Errorf("exit code = %d, want %d", got, exitError)
+			}
+		})
+	}
+}
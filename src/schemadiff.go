@@ -0,0 +1,331 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// schemaIgnoreFileName lists schema paths to leave out of diffs, one per line
+const schemaIgnoreFileName = ".basicignore"
+
+// defaultIgnorePaths are always expected to differ between local and remote
+var defaultIgnorePaths = []string{"version"}
+
+// schemaChange is a single difference between two schemas, e.g.
+// {kind: "changed", path: "tables.users.fields.email.type", old: "string", new: "number"}
+type schemaChange struct {
+	Kind string      `json:"kind"` // "added", "removed", "changed" or "renamed"
+	Path string      `json:"path"`
+	Old  interface{} `json:"old,omitempty"`
+	New  interface{} `json:"new,omitempty"`
+	// where a renamed field ended up
+	NewPath string `json:"new_path,omitempty"`
+}
+
+func parseSchemaJSON(schema string) (map[string]interface{}, error) {
+	var schemaData map[string]interface{}
+	if err := json.Unmarshal([]byte(schema), &schemaData); err != nil {
+		return nil, fmt.Errorf("error parsing schema: %v", err)
+	}
+	return schemaData, nil
+}
+
+// schemaRequestBody builds the {"schema": ...} body sent to the API in a
+// canonical form: keys sorted (encoding/json always sorts map keys), no
+// whitespace, and numbers kept exactly as written instead of round-tripping
+// through float64. The same logical schema always produces the same bytes.
+func schemaRequestBody(schema string) ([]byte, error) {
+	decoder := json.NewDecoder(strings.NewReader(schema))
+	decoder.UseNumber()
+
+	var schemaData map[string]interface{}
+	if err := decoder.Decode(&schemaData); err != nil {
+		return nil, fmt.Errorf("error parsing schema JSON: %v", err)
+	}
+
+	return json.Marshal(map[string]interface{}{"schema": schemaData})
+}
+
+// errNoSchemaVersion means the schema has no "version" key at all, as
+// opposed to a version of 0, which a brand-new schema legitimately has
+var errNoSchemaVersion = errors.New("no version found")
+
+// readSchemaVersion returns the schema's version, telling a missing version apart
+// from one that isn't a number
+func readSchemaVersion(schemaData map[string]interface{}) (float64, error) {
+	value, ok := schemaData["version"]
+	if !ok || value == nil {
+		return 0, errNoSchemaVersion
+	}
+	version, ok := value.(float64)
+	if !ok {
+		return 0, fmt.Errorf("version must be a number, got %s", compactJSON(value))
+	}
+	return version, nil
+}
+
+// diffSchemas returns the changes needed to go from oldSchema to newSchema,
+// down to individual field properties, sorted by path
+func diffSchemas(oldSchema, newSchema map[string]interface{}) []schemaChange {
+	changes := diffValues("", oldSchema, newSchema)
+	sort.SliceStable(changes, func(i, j int) bool {
+		return changes[i].Path < changes[j].Path
+	})
+	return changes
+}
+
+func diffValues(path string, oldValue, newValue interface{}) []schemaChange {
+	oldMap, oldIsMap := oldValue.(map[string]interface{})
+	newMap, newIsMap := newValue.(map[string]interface{})
+
+	if !oldIsMap || !newIsMap {
+		if reflect.DeepEqual(oldValue, newValue) {
+			return nil
+		}
+		return []schemaChange{{Kind: "changed", Path: path, Old: oldValue, New: newValue}}
+	}
+
+	var changes []schemaChange
+	for key, oldChild := range oldMap {
+		childPath := joinSchemaPath(path, key)
+		newChild, ok := newMap[key]
+		if !ok {
+			changes = append(changes, schemaChange{Kind: "removed", Path: childPath, Old: oldChild})
+			continue
+		}
+		changes = append(changes, diffValues(childPath, oldChild, newChild)...)
+	}
+	for key, newChild := range newMap {
+		if _, ok := oldMap[key]; !ok {
+			changes = append(changes, schemaChange{Kind: "added", Path: joinSchemaPath(path, key), New: newChild})
+		}
+	}
+	return changes
+}
+
+// detectRenamedFields turns a removed field and an added field with the exact
+// same definition in the same table into a single "renamed" change
+func detectRenamedFields(changes []schemaChange) []schemaChange {
+	renamedTo := map[int]int{}
+	used := map[int]bool{}
+	for i, removed := range changes {
+		table, ok := schemaFieldTable(removed)
+		if !ok || removed.Kind != "removed" {
+			continue
+		}
+		for j, added := range changes {
+			if used[j] || added.Kind != "added" {
+				continue
+			}
+			if addedTable, ok := schemaFieldTable(added); ok && addedTable == table && reflect.DeepEqual(removed.Old, added.New) {
+				renamedTo[i] = j
+				used[j] = true
+				break
+			}
+		}
+	}
+	if len(renamedTo) == 0 {
+		return changes
+	}
+
+	var result []schemaChange
+	for i, c := range changes {
+		if used[i] {
+			continue
+		}
+		if j, ok := renamedTo[i]; ok {
+			c = schemaChange{Kind: "renamed", Path: c.Path, NewPath: changes[j].Path, Old: c.Old}
+		}
+		result = append(result, c)
+	}
+	return result
+}
+
+// schemaFieldTable returns the table of a change to a whole field, i.e. one
+// at tables.<table>.fields.<field>
+func schemaFieldTable(c schemaChange) (string, bool) {
+	parts := strings.Split(c.Path, ".")
+	if len(parts) != 4 || parts[0] != "tables" || parts[2] != "fields" {
+		return "", false
+	}
+	return parts[1], true
+}
+
+// changedTables returns the names of the tables touched by changes
+func changedTables(changes []schemaChange) []string {
+	seen := map[string]bool{}
+	tables := []string{}
+	for _, c := range changes {
+		table, ok := schemaPathTable(c.Path)
+		if !ok || seen[table] {
+			continue
+		}
+		seen[table] = true
+		tables = append(tables, table)
+	}
+	return tables
+}
+
+// schemaPathTable returns the table a path like tables.users.fields.email is in
+func schemaPathTable(path string) (string, bool) {
+	parts := strings.SplitN(path, ".", 3)
+	if len(parts) < 2 || parts[0] != "tables" {
+		return "", false
+	}
+	return parts[1], true
+}
+
+func joinSchemaPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+// formatSchemaChange renders a change as a single line, e.g.
+// "~ tables.users.fields.email.type: "string" -> "number""
+func formatSchemaChange(c schemaChange) string {
+	switch c.Kind {
+	case "added":
+		return fmt.Sprintf("+ %s", c.Path)
+	case "removed":
+		return fmt.Sprintf("- %s", c.Path)
+	case "renamed":
+		return fmt.Sprintf("> %s -> %s", c.Path, c.NewPath)
+	default:
+		return fmt.Sprintf("~ %s: %s -> %s", c.Path, compactJSON(c.Old), compactJSON(c.New))
+	}
+}
+
+func compactJSON(v interface{}) string {
+	out, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprintf("%v", v)
+	}
+	return string(out)
+}
+
+// schemaIgnorePaths returns the paths to leave out of diffs: the defaults,
+// anything listed in .basicignore and any --ignore flags
+func schemaIgnorePaths() []string {
+	paths := append([]string{}, defaultIgnorePaths...)
+
+	if file, err := os.Open(schemaIgnoreFileName); err == nil {
+		defer file.Close()
+		scanner := bufio.NewScanner(file)
+		for scanner.Scan() {
+			line := strings.TrimSpace(scanner.Text())
+			if line == "" || strings.HasPrefix(line, "#") {
+				continue
+			}
+			paths = append(paths, line)
+		}
+	}
+
+	return append(paths, flagValues("--ignore")...)
+}
+
+// filterIgnoredChanges drops changes at or below any of the ignored paths.
+// A "*" segment matches any single key, e.g. tables.*.fields.*.description
+func filterIgnoredChanges(changes []schemaChange, ignorePaths []string) []schemaChange {
+	var filtered []schemaChange
+	for _, c := range changes {
+		ignored := false
+		for _, pattern := range ignorePaths {
+			if matchSchemaPath(pattern, c.Path) {
+				ignored = true
+				break
+			}
+		}
+		if !ignored {
+			filtered = append(filtered, c)
+		}
+	}
+	return filtered
+}
+
+func matchSchemaPath(pattern, path string) bool {
+	patternParts := strings.Split(strings.TrimPrefix(pattern, "."), ".")
+	pathParts := strings.Split(path, ".")
+	if len(patternParts) > len(pathParts) {
+		return false
+	}
+	for i, part := range patternParts {
+		if part != "*" && part != pathParts[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// breakingChange is a local change that the remote schema can't migrate to
+// without losing or invalidating existing data
+type breakingChange struct {
+	Path   string `json:"path"`
+	Reason string `json:"reason"`
+}
+
+// breakingChanges compares newSchema with the currently published oldSchema,
+// reporting removed tables, changed field types, removed required fields and
+// fields that existing rows can't satisfy because they became required
+func breakingChanges(oldSchema, newSchema map[string]interface{}) []breakingChange {
+	var breaking []breakingChange
+
+	oldTables, _ := oldSchema["tables"].(map[string]interface{})
+	newTables, _ := newSchema["tables"].(map[string]interface{})
+	for tableName, oldTable := range oldTables {
+		tablePath := joinSchemaPath("tables", tableName)
+		newTable, ok := newTables[tableName].(map[string]interface{})
+		if !ok {
+			breaking = append(breaking, breakingChange{Path: tablePath, Reason: fmt.Sprintf("table %s was removed", tableName)})
+			continue
+		}
+
+		oldFields, _ := oldTable.(map[string]interface{})["fields"].(map[string]interface{})
+		newFields, _ := newTable["fields"].(map[string]interface{})
+		for fieldName, oldField := range oldFields {
+			fieldPath := joinSchemaPath(tablePath, "fields."+fieldName)
+			oldFieldData, _ := oldField.(map[string]interface{})
+			oldRequired, _ := oldFieldData["required"].(bool)
+
+			newFieldData, ok := newFields[fieldName].(map[string]interface{})
+			if !ok {
+				if oldRequired {
+					breaking = append(breaking, breakingChange{Path: fieldPath,
+						Reason: fmt.Sprintf("required field %s.%s was removed", tableName, fieldName)})
+				}
+				continue
+			}
+
+			if oldType, newType := oldFieldData["type"], newFieldData["type"]; !reflect.DeepEqual(oldType, newType) {
+				breaking = append(breaking, breakingChange{Path: fieldPath + ".type",
+					Reason: fmt.Sprintf("%s.%s changed type from %s to %s", tableName, fieldName, compactJSON(oldType), compactJSON(newType))})
+			}
+			if newRequired, _ := newFieldData["required"].(bool); newRequired && !oldRequired {
+				breaking = append(breaking, breakingChange{Path: fieldPath + ".required",
+					Reason: fmt.Sprintf("%s.%s is now required, but existing rows may not have it", tableName, fieldName)})
+			}
+		}
+
+		for fieldName, newField := range newFields {
+			if _, existed := oldFields[fieldName]; existed {
+				continue
+			}
+			if newRequired, _ := newField.(map[string]interface{})["required"].(bool); newRequired {
+				breaking = append(breaking, breakingChange{Path: joinSchemaPath(tablePath, "fields."+fieldName),
+					Reason: fmt.Sprintf("new field %s.%s is required, but existing rows don't have it", tableName, fieldName)})
+			}
+		}
+	}
+
+	sort.SliceStable(breaking, func(i, j int) bool {
+		return breaking[i].Path < breaking[j].Path
+	})
+	return breaking
+}
@@ -0,0 +1,135 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// basic field types and their JSON Schema equivalents
+var jsonSchemaTypes = map[string]string{
+	"string":  "string",
+	"number":  "number",
+	"boolean": "boolean",
+	"json":    "object",
+}
+
+// performSchemaExport converts the local schema to --format (jsonschema or
+// openapi) and prints it, or writes it to --out
+func performSchemaExport() tea.Msg {
+	format := flagValue("--format")
+	if format == "" {
+		format = "jsonschema"
+	}
+	if format != "jsonschema" && format != "openapi" {
+		return errorScreenMsg{errorMessage: fmt.Sprintf("unknown format %q, use --format jsonschema or --format openapi", format), code: "usage"}
+	}
+
+	schema, err := readSchemaFromConfig()
+	if err != nil {
+		return errorScreenMsg{errorMessage: fmt.Sprintf("error reading schema: %v", err)}
+	}
+
+	schemaData, err := parseSchemaJSON(schema)
+	if err != nil {
+		return errorScreenMsg{errorMessage: err.Error(), code: "schema"}
+	}
+
+	var doc map[string]interface{}
+	if format == "openapi" {
+		doc = openAPIDocument(schemaData)
+	} else {
+		doc = jsonSchemaDocument(schemaData)
+	}
+
+	out, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return errorScreenMsg{errorMessage: fmt.Sprintf("error encoding %s: %v", format, err)}
+	}
+
+	if filename := flagValue("--out"); filename != "" {
+		if err := os.WriteFile(filename, append(out, '\n'), 0644); err != nil {
+			return errorScreenMsg{errorMessage: fmt.Sprintf("error writing %s: %v", filename, err)}
+		}
+		fmt.Printf("Exported schema to %s\n", filename)
+		return tea.Quit()
+	}
+
+	fmt.Println(string(out))
+	return tea.Quit()
+}
+
+// tableSchemas maps each table in a basic schema to a JSON Schema object
+func tableSchemas(schemaData map[string]interface{}) map[string]interface{} {
+	schemas := map[string]interface{}{}
+
+	tables, _ := schemaData["tables"].(map[string]interface{})
+	for name, table := range tables {
+		tableData, _ := table.(map[string]interface{})
+		fields, _ := tableData["fields"].(map[string]interface{})
+
+		properties := map[string]interface{}{}
+		required := []string{}
+		for fieldName, field := range fields {
+			fieldData, _ := field.(map[string]interface{})
+
+			property := map[string]interface{}{}
+			if fieldType, ok := fieldData["type"].(string); ok {
+				if t, ok := jsonSchemaTypes[fieldType]; ok {
+					property["type"] = t
+				}
+			}
+			properties[fieldName] = property
+
+			if r, _ := fieldData["required"].(bool); r {
+				required = append(required, fieldName)
+			}
+		}
+
+		tableSchema := map[string]interface{}{
+			"type":       "object",
+			"properties": properties,
+		}
+		if len(required) > 0 {
+			sort.Strings(required)
+			tableSchema["required"] = required
+		}
+		schemas[name] = tableSchema
+	}
+
+	return schemas
+}
+
+func jsonSchemaDocument(schemaData map[string]interface{}) map[string]interface{} {
+	doc := map[string]interface{}{
+		"$schema": "https://json-schema.org/draft/2020-12/schema",
+		"$defs":   tableSchemas(schemaData),
+	}
+	if projectID, ok := schemaData["project_id"].(string); ok {
+		doc["title"] = projectID
+	}
+	return doc
+}
+
+func openAPIDocument(schemaData map[string]interface{}) map[string]interface{} {
+	title, _ := schemaData["project_id"].(string)
+	version := "1"
+	if v, ok := schemaData["version"].(float64); ok {
+		version = fmt.Sprintf("%.0f", v)
+	}
+
+	return map[string]interface{}{
+		"openapi": "3.1.0",
+		"info": map[string]interface{}{
+			"title":   title,
+			"version": version,
+		},
+		"paths": map[string]interface{}{},
+		"components": map[string]interface{}{
+			"schemas": tableSchemas(schemaData),
+		},
+	}
+}
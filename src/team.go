@@ -0,0 +1,459 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/charmbracelet/bubbles/table"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/huh"
+	"github.com/charmbracelet/lipgloss"
+	"golang.org/x/oauth2"
+)
+
+const teamUsage = `Usage:
+  basic team list
+  basic team invite <email> [--role admin|member]
+  basic team remove <email>`
+
+// teamRoles are the roles members can be given, the owner's can't be changed
+var teamRoles = []string{"admin", "member"}
+
+type teamMember struct {
+	ID    string `json:"id"`
+	Email string `json:"email"`
+	Name  string `json:"name,omitempty"`
+	Role  string `json:"role"`
+	// "active", or "invited" until the invite is accepted
+	Status   string    `json:"status"`
+	JoinedAt time.Time `json:"joined_at"`
+}
+
+// teamMsg carries the members 'basic team list' shows in its table
+type teamMsg struct {
+	token     *oauth2.Token
+	projectID string
+	members   []teamMember
+}
+
+// performTeam implements 'basic team list/invite/remove' for the project in
+// the local config
+func performTeam() tea.Msg {
+	args := positionalArgs()
+	action := "list"
+	if len(args) > 0 {
+		action = args[0]
+	}
+
+	token, err := loadToken()
+	if err != nil || token == nil {
+		return errorScreenMsg{errorMessage: loggedOutError(err)}
+	}
+	projectID, err := configProjectID()
+	if err != nil {
+		return errorScreenMsg{errorMessage: err.Error()}
+	}
+
+	switch action {
+	case "list":
+		members, err := getTeamMembers(token, projectID)
+		if err != nil {
+			return errorScreenMsg{errorMessage: err.Error()}
+		}
+		if isInteractive() && len(members) > 0 {
+			return teamMsg{token: token, projectID: projectID, members: members}
+		}
+		printTeamMembers(members)
+		return tea.Quit()
+	case "invite":
+		if len(args) < 2 || !strings.Contains(args[1], "@") {
+			return usageMsg{text: teamUsage}
+		}
+		role := flagValue("--role")
+		if role == "" {
+			role = "member"
+		}
+		if !isTeamRole(role) {
+			return errorScreenMsg{errorMessage: fmt.Sprintf("invalid role %q, use one of: %s", role, strings.Join(teamRoles, ", ")), code: "usage"}
+		}
+		member, err := inviteTeamMember(token, projectID, args[1], role)
+		if err != nil {
+			return errorScreenMsg{errorMessage: err.Error()}
+		}
+		if hasFlag("--json") {
+			out, _ := json.MarshalIndent(member, "", "  ")
+			fmt.Println(string(out))
+		} else {
+			fmt.Printf("Invited %s to %s as %s\n", member.Email, projectID, member.Role)
+		}
+		return tea.Quit()
+	case "remove":
+		if len(args) < 2 {
+			return usageMsg{text: teamUsage}
+		}
+		members, err := getTeamMembers(token, projectID)
+		if err != nil {
+			return errorScreenMsg{errorMessage: err.Error()}
+		}
+		member, ok := findTeamMember(members, args[1])
+		if !ok {
+			return errorScreenMsg{errorMessage: fmt.Sprintf("%s is not a member of %s, run 'basic team list' to see who is", args[1], projectID)}
+		}
+		if member.Role == "owner" {
+			return errorScreenMsg{errorMessage: "the project owner can't be removed"}
+		}
+
+		remove := func() tea.Msg {
+			if err := removeTeamMember(token, projectID, member.ID); err != nil {
+				return errorScreenMsg{errorMessage: err.Error()}
+			}
+			if hasFlag("--json") {
+				out, _ := json.Marshal(map[string]interface{}{"id": member.ID, "email": member.Email, "removed": true})
+				fmt.Println(string(out))
+			} else {
+				fmt.Printf("Removed %s from %s\n", member.Email, projectID)
+			}
+			return tea.Quit()
+		}
+		if assumeYes() {
+			return remove()
+		}
+		if !isInteractive() {
+			return errorScreenMsg{errorMessage: "removing a member takes away their access, pass --yes to confirm"}
+		}
+		return confirmMsg{
+			title:       fmt.Sprintf("Remove %s from %s?", member.Email, projectID),
+			description: "They lose access to the project right away.",
+			affirmative: "Yes, remove",
+			run:         remove,
+			cancelled:   "Remove cancelled",
+		}
+	}
+	return usageMsg{text: teamUsage}
+}
+
+func isTeamRole(role string) bool {
+	for _, r := range teamRoles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+// findTeamMember finds a member by email, case-insensitively, or by user ID
+func findTeamMember(members []teamMember, who string) (teamMember, bool) {
+	for _, member := range members {
+		if strings.EqualFold(member.Email, who) || member.ID == who {
+			return member, true
+		}
+	}
+	return teamMember{}, false
+}
+
+func getTeamMembers(token *oauth2.Token, projectID string) ([]teamMember, error) {
+	url := "https://api.basic.tech/project/" + projectID + "/members"
+	resp, err := authClient(token).Get(url)
+	if err != nil {
+		return nil, apiRequestError("error fetching team members", url, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("received non-200 response fetching team members: %d - %s", resp.StatusCode, string(body))
+	}
+
+	var response struct {
+		Data []teamMember `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return nil, fmt.Errorf("error parsing JSON response: %v", err)
+	}
+	return response.Data, nil
+}
+
+func inviteTeamMember(token *oauth2.Token, projectID, email, role string) (teamMember, error) {
+	url := "https://api.basic.tech/project/" + projectID + "/members"
+	member, err := teamMemberRequest(token, http.MethodPost, url, map[string]string{"email": email, "role": role})
+	if err != nil {
+		return teamMember{}, fmt.Errorf("error inviting %s: %v", email, err)
+	}
+	return member, nil
+}
+
+func updateTeamMemberRole(token *oauth2.Token, projectID, memberID, role string) (teamMember, error) {
+	requestURL := "https://api.basic.tech/project/" + projectID + "/members/" + url.PathEscape(memberID)
+	member, err := teamMemberRequest(token, http.MethodPatch, requestURL, map[string]string{"role": role})
+	if err != nil {
+		return teamMember{}, fmt.Errorf("error changing role: %v", err)
+	}
+	return member, nil
+}
+
+// teamMemberRequest sends body to the members API and returns the member it
+// responds with
+func teamMemberRequest(token *oauth2.Token, method, requestURL string, body map[string]string) (teamMember, error) {
+	jsonBody, err := json.Marshal(body)
+	if err != nil {
+		return teamMember{}, fmt.Errorf("error encoding request: %v", err)
+	}
+	req, err := http.NewRequest(method, requestURL, bytes.NewReader(jsonBody))
+	if err != nil {
+		return teamMember{}, fmt.Errorf("error creating request: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := authClient(token).Do(req)
+	if err != nil {
+		return teamMember{}, apiRequestError("error calling the members API", requestURL, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+		respBody, _ := io.ReadAll(resp.Body)
+		return teamMember{}, fmt.Errorf("received non-200 response: %d - %s", resp.StatusCode, string(respBody))
+	}
+
+	var response struct {
+		Data teamMember `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return teamMember{}, fmt.Errorf("error parsing JSON response: %v", err)
+	}
+	return response.Data, nil
+}
+
+func removeTeamMember(token *oauth2.Token, projectID, memberID string) error {
+	requestURL := "https://api.basic.tech/project/" + projectID + "/members/" + url.PathEscape(memberID)
+	req, err := http.NewRequest(http.MethodDelete, requestURL, nil)
+	if err != nil {
+		return fmt.Errorf("error creating request: %v", err)
+	}
+
+	resp, err := authClient(token).Do(req)
+	if err != nil {
+		return apiRequestError("error removing team member", requestURL, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("received non-200 response removing team member: %d - %s", resp.StatusCode, string(body))
+	}
+	return nil
+}
+
+func printTeamMembers(members []teamMember) {
+	if hasFlag("--json") {
+		out, _ := json.MarshalIndent(members, "", "  ")
+		fmt.Println(string(out))
+		return
+	}
+	if len(members) == 0 {
+		fmt.Println("This project has no members yet. Invite one with 'basic team invite <email>'")
+		return
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "EMAIL\tNAME\tROLE\tSTATUS\tJOINED")
+	for _, member := range members {
+		fmt.Fprintln(w, strings.Join(teamMemberRow(member), "\t"))
+	}
+	w.Flush()
+}
+
+func teamMemberRow(member teamMember) []string {
+	joined := ""
+	if !member.JoinedAt.IsZero() {
+		joined = member.JoinedAt.Local().Format("2006-01-02")
+	}
+	return []string{member.Email, member.Name, member.Role, member.Status, joined}
+}
+
+// teamTableModel is the interactive 'basic team list', where 'r' changes the
+// selected member's role
+type teamTableModel struct {
+	token     *oauth2.Token
+	projectID string
+	members   []teamMember
+	table     table.Model
+	height    int
+	// picks the selected member's new role
+	rolePicker   *huh.Form
+	saving       bool
+	notification string
+	err          error
+}
+
+type teamRoleMsg struct {
+	member teamMember
+	err    error
+}
+
+func openTeamTable(msg teamMsg) (tea.Model, tea.Cmd) {
+	t := table.New(
+		table.WithColumns([]table.Column{
+			{Title: "EMAIL", Width: 32},
+			{Title: "NAME", Width: 20},
+			{Title: "ROLE", Width: 8},
+			{Title: "STATUS", Width: 8},
+			{Title: "JOINED", Width: 10},
+		}),
+		table.WithFocused(true),
+	)
+	t.SetStyles(tableStyles())
+
+	m := teamTableModel{token: msg.token, projectID: msg.projectID, members: msg.members, table: t}
+	m.refreshTable()
+	return m, tea.WindowSize()
+}
+
+func (m *teamTableModel) refreshTable() {
+	rows := []table.Row{}
+	for _, member := range m.members {
+		rows = append(rows, teamMemberRow(member))
+	}
+	m.table.SetRows(rows)
+
+	height := len(rows) + 1
+	if m.height > 0 {
+		height = min(height, m.height-8)
+	}
+	m.table.SetHeight(max(height, 3))
+}
+
+func (m teamTableModel) Init() tea.Cmd {
+	return nil
+}
+
+func (m teamTableModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if m.rolePicker != nil {
+		return m.updateRolePicker(msg)
+	}
+
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.height = msg.Height
+		m.refreshTable()
+		return m, nil
+	case teamRoleMsg:
+		m.saving = false
+		m.err = msg.err
+		if msg.err == nil {
+			for i := range m.members {
+				if m.members[i].ID == msg.member.ID {
+					m.members[i] = msg.member
+				}
+			}
+			m.refreshTable()
+			m.notification = fmt.Sprintf("%s is now %s", msg.member.Email, withArticle(msg.member.Role))
+		}
+		return m, nil
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "ctrl+c", "esc", "q":
+			return m, tea.Quit
+		case "r", "enter":
+			cursor := m.table.Cursor()
+			if m.saving || cursor < 0 || cursor >= len(m.members) {
+				return m, nil
+			}
+			member := m.members[cursor]
+			if member.Role == "owner" {
+				m.notification = "the owner's role can't be changed"
+				return m, nil
+			}
+			m.rolePicker = newRolePicker(member)
+			return m, m.rolePicker.Init()
+		}
+	}
+
+	var cmd tea.Cmd
+	m.table, cmd = m.table.Update(msg)
+	return m, cmd
+}
+
+func newRolePicker(member teamMember) *huh.Form {
+	options := []huh.Option[string]{}
+	for _, role := range teamRoles {
+		options = append(options, huh.NewOption(role, role).Selected(role == member.Role))
+	}
+	return huh.NewForm(
+		huh.NewGroup(
+			huh.NewSelect[string]().
+				Key("role").
+				Title(fmt.Sprintf("Role of %s", member.Email)).
+				Options(options...),
+		),
+	).WithShowHelp(false)
+}
+
+// updateRolePicker runs the role picker, esc goes back to the table
+func (m teamTableModel) updateRolePicker(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if key, ok := msg.(tea.KeyMsg); ok {
+		switch key.String() {
+		case "ctrl+c":
+			return m, tea.Quit
+		case "esc":
+			m.rolePicker = nil
+			return m, nil
+		}
+	}
+
+	form, cmd := m.rolePicker.Update(msg)
+	if f, ok := form.(*huh.Form); ok {
+		m.rolePicker = f
+		switch f.State {
+		case huh.StateCompleted:
+			m.rolePicker = nil
+			member := m.members[m.table.Cursor()]
+			role := f.GetString("role")
+			if role == member.Role {
+				return m, nil
+			}
+			m.saving = true
+			m.err = nil
+			m.notification = ""
+			token, projectID := m.token, m.projectID
+			return m, func() tea.Msg {
+				updated, err := updateTeamMemberRole(token, projectID, member.ID, role)
+				return teamRoleMsg{member: updated, err: err}
+			}
+		case huh.StateAborted:
+			m.rolePicker = nil
+			return m, nil
+		}
+	}
+	return m, cmd
+}
+
+func (m teamTableModel) View() string {
+	if m.rolePicker != nil {
+		return "\n" + m.rolePicker.View() + "\n"
+	}
+
+	var s strings.Builder
+	s.WriteString(fmt.Sprintf("\nTeam of %s\n\n", m.projectID))
+	s.WriteString(m.table.View() + "\n")
+
+	switch {
+	case m.saving:
+		s.WriteString("\nSaving role...\n")
+	case m.err != nil:
+		s.WriteString("\n" + lipgloss.NewStyle().Foreground(lipgloss.Color("9")).Render(fmt.Sprintf("Error: %v", m.err)) + "\n")
+	case m.notification != "":
+		s.WriteString("\n" + lipgloss.NewStyle().Foreground(lipgloss.Color("57")).Render(m.notification) + "\n")
+	}
+
+	s.WriteString("\n" + lipgloss.NewStyle().Foreground(lipgloss.Color("240")).Render("'r' to change role • 'q' to quit") + "\n")
+	return s.String()
+}
@@ -0,0 +1,483 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// validationResult is the machine readable form of a schema validation, for editor integrations
+type validationResult struct {
+	Valid  bool                    `json:"valid"`
+	Errors []validationResultError `json:"errors"`
+	Error  string                  `json:"error,omitempty"`
+	// BreakingChanges is only filled in with --against-remote
+	BreakingChanges []breakingChange `json:"breaking_changes,omitempty"`
+	Tables          int              `json:"tables,omitempty"`
+	Fields          int              `json:"fields,omitempty"`
+
+	// for the --summary line
+	projectID string
+	version   float64
+}
+
+type validationResultError struct {
+	Message string `json:"message"`
+	Path    string `json:"path"`
+	// only known for --offline, which reads the schema as written
+	Line int `json:"line,omitempty"`
+}
+
+func newValidationResult(validation schemaValidation) validationResult {
+	result := validationResult{
+		Valid:  validation.Valid == nil || *validation.Valid,
+		Errors: []validationResultError{},
+	}
+	for _, e := range validation.Errors {
+		path := e.Path
+		if path == "" {
+			path = e.Change.Path
+		}
+		result.Errors = append(result.Errors, validationResultError{Message: e.Message, Path: normalizeSchemaPath(path)})
+	}
+	if len(result.Errors) > 0 {
+		result.Valid = false
+	}
+	return result
+}
+
+// normalizeSchemaPath turns the paths the validation API reports, like the
+// JSON pointer /tables/users/fields/email or tables["users"], into the dotted
+// form used everywhere else, e.g. tables.users.fields.email
+func normalizeSchemaPath(path string) string {
+	path = strings.TrimPrefix(path, "#")
+	path = strings.TrimPrefix(path, "$")
+
+	if strings.HasPrefix(path, "/") {
+		parts := strings.Split(strings.TrimPrefix(path, "/"), "/")
+		for i, part := range parts {
+			parts[i] = strings.NewReplacer("~1", "/", "~0", "~").Replace(part)
+		}
+		return strings.Join(parts, ".")
+	}
+
+	// bracket notation: tables["users"] or tables[0]
+	path = bracketPathPattern.ReplaceAllString(path, ".$1")
+	return strings.Trim(path, ".")
+}
+
+var bracketPathPattern = regexp.MustCompile(`\[["']?([^\]"']*)["']?\]`)
+
+// validateFile validates the schema in filename, or in the local config when
+// filename is "". A filename of "-" reads schema JSON from stdin.
+func validateFile(filename string) (validationResult, error) {
+	if hasFlag("--offline") {
+		if hasFlag("--against-remote") {
+			return validationResult{}, fmt.Errorf("--against-remote compares with the published schema, so it can't be used with --offline")
+		}
+		return validateFileOffline(filename)
+	}
+
+	var schema string
+	var err error
+	if filename == "-" {
+		schema, err = readSchemaFromStdin()
+	} else if filename != "" {
+		schema, err = readSchemaFromFile(filename)
+	} else {
+		schema, err = readSchemaFromConfig()
+	}
+	if err != nil {
+		return validationResult{}, fmt.Errorf("error reading schema: %v", err)
+	}
+
+	validation, err := cachedValidateSchema(schema)
+	if err != nil {
+		return validationResult{}, err
+	}
+
+	result := newValidationResult(validation)
+	if schemaData, err := parseSchemaJSON(schema); err == nil {
+		result.Tables, result.Fields = countTablesAndFields(schemaData)
+		result.projectID, _ = schemaData["project_id"].(string)
+		result.version, _ = readSchemaVersion(schemaData)
+	}
+	if hasFlag("--against-remote") {
+		result.BreakingChanges, err = remoteBreakingChanges(schema)
+		if err != nil {
+			return validationResult{}, err
+		}
+		if len(result.BreakingChanges) > 0 {
+			result.Valid = false
+		}
+	}
+	return result, nil
+}
+
+// remoteBreakingChanges checks that schema is a safe migration from its
+// project's published schema. An unpublished project has nothing to break.
+func remoteBreakingChanges(schema string) ([]breakingChange, error) {
+	schemaData, err := parseSchemaJSON(schema)
+	if err != nil {
+		return nil, err
+	}
+	projectID, _ := schemaData["project_id"].(string)
+	if projectID == "" {
+		return nil, fmt.Errorf("project_id not found in schema")
+	}
+
+	remote, err := getProjectSchema(projectID)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching remote schema: %v", err)
+	}
+	if remote == "" {
+		return nil, nil
+	}
+	remoteData, err := parseSchemaJSON(remote)
+	if err != nil {
+		return nil, err
+	}
+	return breakingChanges(remoteData, schemaData), nil
+}
+
+// countTablesAndFields counts the tables in a schema and the fields across them
+func countTablesAndFields(schemaData map[string]interface{}) (int, int) {
+	tables, _ := schemaData["tables"].(map[string]interface{})
+	fields := 0
+	for _, table := range tables {
+		tableData, _ := table.(map[string]interface{})
+		tableFields, _ := tableData["fields"].(map[string]interface{})
+		fields += len(tableFields)
+	}
+	return len(tables), fields
+}
+
+// validationSummary is the headline for a validation, e.g.
+// "Schema invalid: 3 errors across 2 tables" or "Schema valid: 4 tables, 12 fields"
+func validationSummary(result validationResult) string {
+	if result.Valid {
+		return fmt.Sprintf("Schema valid: %s, %s", countNoun(result.Tables, "table"), countNoun(result.Fields, "field"))
+	}
+
+	tables := map[string]bool{}
+	for _, e := range result.Errors {
+		if table, ok := schemaPathTable(e.Path); ok {
+			tables[table] = true
+		}
+	}
+	for _, c := range result.BreakingChanges {
+		if table, ok := schemaPathTable(c.Path); ok {
+			tables[table] = true
+		}
+	}
+
+	errors := len(result.Errors) + len(result.BreakingChanges)
+	if errors == 0 {
+		return "Schema invalid"
+	}
+	summary := "Schema invalid: " + countNoun(errors, "error")
+	if len(tables) > 0 {
+		summary += " across " + countNoun(len(tables), "table")
+	}
+	return summary
+}
+
+func countNoun(n int, noun string) string {
+	if n == 1 {
+		return fmt.Sprintf("%d %s", n, noun)
+	}
+	return fmt.Sprintf("%d %ss", n, noun)
+}
+
+func formatValidationResult(result validationResult) []string {
+	if result.Valid {
+		return []string{validationSummary(result)}
+	}
+
+	lines := []string{validationSummary(result) + ". Please fix:"}
+	for _, e := range result.Errors {
+		var where []string
+		if e.Path != "" {
+			where = append(where, e.Path)
+		}
+		if e.Line > 0 {
+			where = append(where, fmt.Sprintf("line %d", e.Line))
+		}
+		if len(where) > 0 {
+			lines = append(lines, fmt.Sprintf(" - %s (%s)", e.Message, strings.Join(where, ", ")))
+		} else {
+			lines = append(lines, fmt.Sprintf(" - %s", e.Message))
+		}
+	}
+	for _, c := range result.BreakingChanges {
+		lines = append(lines, fmt.Sprintf(" - breaking: %s (%s)", c.Reason, c.Path))
+	}
+	return lines
+}
+
+func performValidate() tea.Msg {
+	files, err := validateFiles()
+	if err != nil {
+		printValidationError(err)
+		return validateResultMsg{exitCode: exitError}
+	}
+	if files != nil {
+		return performValidateAll(files)
+	}
+
+	filename := flagValue("--file")
+	if readsSchemaFromStdin() {
+		filename = "-"
+	}
+	result, err := validateFile(filename)
+	if err != nil {
+		printValidationError(err)
+		return validateResultMsg{exitCode: exitError}
+	}
+
+	exitCode := exitOK
+	if !result.Valid {
+		exitCode = exitSchema
+	}
+
+	if hasFlag("--json") {
+		out, _ := json.MarshalIndent(result, "", "  ")
+		fmt.Println(string(out))
+		return validateResultMsg{exitCode: exitCode}
+	}
+
+	var lines []string
+	if !hasFlag("--quiet") || !result.Valid {
+		lines = formatValidationResult(result)
+	}
+	if hasFlag("--summary") {
+		status := "valid"
+		if !result.Valid {
+			status = "invalid"
+		}
+		lines = append(lines, resultSummaryLine(status, result.projectID, result.version, len(result.Errors)+len(result.BreakingChanges)))
+	}
+	return validateResultMsg{exitCode: exitCode, lines: lines}
+}
+
+// resultSummaryLine is the single grep-able line printed last with --summary,
+// e.g. "RESULT valid project=<id> version=3 errors=0"
+func resultSummaryLine(status, projectID string, version float64, errors int) string {
+	if projectID == "" {
+		projectID = "-"
+	}
+	return fmt.Sprintf("RESULT %s project=%s version=%.0f errors=%d", status, projectID, version, errors)
+}
+
+// quietValidate reports whether this is a 'validate --quiet' run, which only
+// prints errors. Watching ignores --quiet, as it has its own screen.
+func quietValidate(command string) bool {
+	if !hasFlag("--quiet") || hasFlag("--watch") {
+		return false
+	}
+	return isValidateCommand(command)
+}
+
+// isValidateCommand reports whether command is 'validate' or 'schema validate'
+func isValidateCommand(command string) bool {
+	args := positionalArgs()
+	return command == "validate" || (command == "schema" && len(args) > 0 && args[0] == "validate")
+}
+
+// startValidateWatch re-validates the config on every save, replacing the
+// previous result on screen
+func (m model) startValidateWatch() (tea.Model, tea.Cmd) {
+	filename := flagValue("--file")
+	if filename == "" {
+		var err error
+		filename, err = findConfigFile()
+		if err != nil {
+			return m, func() tea.Msg {
+				return errorScreenMsg{errorMessage: err.Error()}
+			}
+		}
+	}
+
+	m.state = stateWatching
+	m.watchHeader = fmt.Sprintf("Validating %s on every save...", filename)
+	return m, runWatchCheck(filename, validateWatchCheck, true)
+}
+
+func validateWatchCheck(filename string) []string {
+	lines := []string{fmt.Sprintf("[%s] %s", time.Now().Format("15:04:05"), filename)}
+
+	result, err := validateFile(filename)
+	if err != nil {
+		return append(lines, fmt.Sprintf("Error: %v", err))
+	}
+	return append(lines, formatValidationResult(result)...)
+}
+
+// readsSchemaFromStdin reports whether the schema to validate is piped in,
+// with --stdin or a lone "-" as in 'cat schema.json | basic validate -'
+func readsSchemaFromStdin() bool {
+	return hasFlag("--stdin", "-")
+}
+
+func readSchemaFromStdin() (string, error) {
+	content, err := io.ReadAll(os.Stdin)
+	if err != nil {
+		return "", fmt.Errorf("error reading stdin: %v", err)
+	}
+
+	var parsed map[string]interface{}
+	if err := json.Unmarshal(content, &parsed); err != nil {
+		return "", fmt.Errorf("invalid schema JSON on stdin: %v", err)
+	}
+
+	prettyJSON, err := json.MarshalIndent(parsed, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("error formatting schema JSON: %v", err)
+	}
+	return string(prettyJSON), nil
+}
+
+func printValidationError(err error) {
+	if hasFlag("--json") {
+		out, _ := json.MarshalIndent(validationResult{Errors: []validationResultError{}, Error: err.Error()}, "", "  ")
+		fmt.Println(string(out))
+		return
+	}
+	fmt.Printf("Error: %v\n", err)
+}
+
+// validateFiles returns the configs to validate when more than one was asked
+// for, with --all or several (or globbed) --file flags. It returns nil for a
+// plain single file validation.
+func validateFiles() ([]string, error) {
+	var files []string
+	multiple := hasFlag("--all")
+
+	if hasFlag("--all") {
+		found, err := findConfigFiles(".")
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, found...)
+	}
+
+	for _, pattern := range flagValues("--file") {
+		if !strings.ContainsAny(pattern, "*?[") {
+			files = append(files, pattern)
+			continue
+		}
+		multiple = true
+		matches, err := filepath.Glob(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid pattern %s: %v", pattern, err)
+		}
+		files = append(files, matches...)
+	}
+
+	if !multiple && len(files) <= 1 {
+		return nil, nil
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no basic config files found")
+	}
+	return files, nil
+}
+
+// findConfigFiles returns every basic config below root, skipping
+// dependencies and hidden directories
+func findConfigFiles(root string) ([]string, error) {
+	var files []string
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			name := d.Name()
+			if path != root && (name == "node_modules" || strings.HasPrefix(name, ".")) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		for _, name := range configFileNames {
+			if d.Name() == name {
+				files = append(files, path)
+			}
+		}
+		return nil
+	})
+	return files, err
+}
+
+// validateResultMsg reports the result of validating one or more configs
+type validateResultMsg struct {
+	exitCode int
+	lines    []string
+}
+
+type fileValidationResult struct {
+	File string `json:"file"`
+	validationResult
+}
+
+// performValidateAll validates each file and prints a pass/fail summary,
+// failing if any of them is invalid
+func performValidateAll(files []string) tea.Msg {
+	results := []fileValidationResult{}
+	exitCode := exitOK
+	for _, file := range files {
+		result, err := validateFile(file)
+		if err != nil {
+			result = validationResult{Errors: []validationResultError{}, Error: err.Error()}
+			exitCode = max(exitCode, exitError)
+		} else if !result.Valid {
+			exitCode = exitSchema
+		}
+		results = append(results, fileValidationResult{File: file, validationResult: result})
+	}
+
+	if hasFlag("--json") {
+		out, _ := json.MarshalIndent(results, "", "  ")
+		fmt.Println(string(out))
+		return validateResultMsg{exitCode: exitCode}
+	}
+
+	quiet := hasFlag("--quiet")
+	lines := []string{}
+	passed := 0
+	for _, r := range results {
+		switch {
+		case r.Error != "":
+			lines = append(lines, fmt.Sprintf("FAIL %s: %s", r.File, r.Error))
+		case r.Valid:
+			passed++
+			if !quiet {
+				lines = append(lines, fmt.Sprintf("ok   %s", r.File))
+			}
+		default:
+			lines = append(lines, fmt.Sprintf("FAIL %s", r.File))
+			for _, line := range formatValidationResult(r.validationResult)[1:] {
+				lines = append(lines, "  "+line)
+			}
+		}
+	}
+	if !quiet {
+		lines = append(lines, "", fmt.Sprintf("%d of %d configs valid", passed, len(results)))
+	}
+	if hasFlag("--summary") {
+		status := "valid"
+		if passed < len(results) {
+			status = "invalid"
+		}
+		lines = append(lines, fmt.Sprintf("RESULT %s configs=%d failed=%d", status, len(results), len(results)-passed))
+	}
+
+	return validateResultMsg{exitCode: exitCode, lines: lines}
+}
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+const (
+	// validation results older than this are checked again, in case the
+	// API's rules changed
+	validationCacheTTL = time.Hour
+	// only the most recently validated schemas are remembered
+	validationCacheSize = 20
+)
+
+// validationCacheEntry is the validation API's answer for one schema,
+// keyed by a hash of the schema content
+type validationCacheEntry struct {
+	Validation schemaValidation `json:"validation"`
+	CheckedAt  time.Time        `json:"checked_at"`
+}
+
+// cached per profile, as each profile may see different projects
+func getValidationCacheFilePath() (string, error) {
+	profileDir, err := getProfileDir(activeProfile())
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(profileDir, validationCacheFileName), nil
+}
+
+func loadValidationCache() map[string]validationCacheEntry {
+	cache := map[string]validationCacheEntry{}
+
+	cacheFilePath, err := getValidationCacheFilePath()
+	if err != nil {
+		return cache
+	}
+	data, err := os.ReadFile(cacheFilePath)
+	if err != nil {
+		return cache
+	}
+	if err := json.Unmarshal(data, &cache); err != nil {
+		return map[string]validationCacheEntry{}
+	}
+	return cache
+}
+
+func saveValidationCache(cache map[string]validationCacheEntry) error {
+	// drop the oldest entries so the file doesn't grow forever
+	if len(cache) > validationCacheSize {
+		keys := make([]string, 0, len(cache))
+		for key := range cache {
+			keys = append(keys, key)
+		}
+		sort.Slice(keys, func(i, j int) bool {
+			return cache[keys[i]].CheckedAt.After(cache[keys[j]].CheckedAt)
+		})
+		for _, key := range keys[validationCacheSize:] {
+			delete(cache, key)
+		}
+	}
+
+	cacheFilePath, err := getValidationCacheFilePath()
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(cache)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(cacheFilePath), 0700); err != nil {
+		return err
+	}
+	return os.WriteFile(cacheFilePath, data, 0600)
+}
+
+func schemaHash(schema string) string {
+	sum := sha256.Sum256([]byte(schema))
+	return hex.EncodeToString(sum[:])
+}
+
+// cachedValidateSchema validates schema, reusing the last result when the
+// schema content hasn't changed since. Failed requests are never cached.
+func cachedValidateSchema(schema string) (schemaValidation, error) {
+	key := schemaHash(schema)
+	cache := loadValidationCache()
+	if entry, ok := cache[key]; ok && time.Since(entry.CheckedAt) < validationCacheTTL {
+		return entry.Validation, nil
+	}
+
+	validation, err := validateSchema(schema)
+	if err != nil {
+		return validation, err
+	}
+
+	cache[key] = validationCacheEntry{Validation: validation, CheckedAt: time.Now()}
+	saveValidationCache(cache)
+	return validation, nil
+}
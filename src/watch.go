@@ -0,0 +1,261 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/fsnotify/fsnotify"
+)
+
+const (
+	// editors often write a file several times per save, so wait for it to settle
+	watchDebounce = 300 * time.Millisecond
+	// number of output lines kept on screen while watching
+	maxWatchMessages = 40
+)
+
+// watchCheck runs on every save of the watched file and returns the lines to show
+type watchCheck func(filename string) []string
+
+type fileWatchMsg struct {
+	filename string
+	check    watchCheck
+	lines    []string
+	// replace the previous output instead of appending to it
+	clear bool
+}
+
+// fileWatcher signals changed on every write to the watched file. Changes
+// made while nothing waits for them, like during a check, are kept as one.
+type fileWatcher struct {
+	changed chan struct{}
+}
+
+var (
+	fileWatchersMu sync.Mutex
+	fileWatchers   = map[string]*fileWatcher{}
+)
+
+// watchFile starts watching filename, once per file. It watches the file's
+// directory rather than the file, so that editors that save by replacing the
+// file (vim, jetbrains) are picked up the same way.
+func watchFile(filename string) (*fileWatcher, error) {
+	path, err := filepath.Abs(filename)
+	if err != nil {
+		return nil, fmt.Errorf("error watching %s: %v", filename, err)
+	}
+
+	fileWatchersMu.Lock()
+	defer fileWatchersMu.Unlock()
+	if w, ok := fileWatchers[path]; ok {
+		return w, nil
+	}
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, fmt.Errorf("error watching %s: %v", filename, err)
+	}
+	if err := watcher.Add(filepath.Dir(path)); err != nil {
+		watcher.Close()
+		return nil, fmt.Errorf("error watching %s: %v", filename, err)
+	}
+
+	w := &fileWatcher{changed: make(chan struct{}, 1)}
+	go func() {
+		for {
+			select {
+			case event, ok := <-watcher.Events:
+				if !ok {
+					return
+				}
+				if event.Name != path || !event.Has(fsnotify.Write|fsnotify.Create) {
+					continue
+				}
+			case _, ok := <-watcher.Errors:
+				if !ok {
+					return
+				}
+				// events were dropped, one of them may have been a save
+			}
+			select {
+			case w.changed <- struct{}{}:
+			default:
+			}
+		}
+	}()
+	fileWatchers[path] = w
+	return w, nil
+}
+
+// waitForFileChange blocks until filename is saved and has stopped changing
+func waitForFileChange(filename string) error {
+	w, err := watchFile(filename)
+	if err != nil {
+		return err
+	}
+	<-w.changed
+	for {
+		select {
+		case <-w.changed:
+		case <-time.After(watchDebounce):
+			return nil
+		}
+	}
+}
+
+// runWatchCheck runs check against filename right away
+func runWatchCheck(filename string, check watchCheck, clear bool) tea.Cmd {
+	return func() tea.Msg {
+		// watch before checking, so a save during the check isn't missed
+		if _, err := watchFile(filename); err != nil {
+			return errorScreenMsg{errorMessage: err.Error()}
+		}
+		// every check should see the current remote schema
+		forgetFetchedSchemas()
+
+		return fileWatchMsg{filename: filename, check: check, lines: check(filename), clear: clear}
+	}
+}
+
+// fileWatchCmd waits for the next save of filename, then runs check
+func fileWatchCmd(msg fileWatchMsg) tea.Cmd {
+	return func() tea.Msg {
+		if err := waitForFileChange(msg.filename); err != nil {
+			return errorScreenMsg{errorMessage: err.Error()}
+		}
+		return runWatchCheck(msg.filename, msg.check, msg.clear)()
+	}
+}
+
+// schemaWatchCheck checks the schema status and, if push is set and the
+// local schema is a valid update, pushes it
+func schemaWatchCheck(push bool) watchCheck {
+	return func(filename string) []string {
+		lines := []string{"", fmt.Sprintf("[%s] checking %s", time.Now().Format("15:04:05"), filename)}
+
+		switch msg := checkStatusCmd().(type) {
+		case statusErrorMsg:
+			lines = append(lines, fmt.Sprintf("Error: %v", msg.err))
+		case statusMsg:
+			lines = append(lines, strings.Split(msg.text, "\n")...)
+			if push && msg.status == "valid" {
+				if _, err := pushProjectSchema(msg.schema); err != nil {
+					lines = append(lines, fmt.Sprintf("Error pushing schema: %v", err))
+				} else {
+					lines = append(lines, "Schema pushed successfully!")
+				}
+			}
+		}
+
+		return lines
+	}
+}
+
+// startConfigWatch implements 'basic watch': on every save of the config the
+// schema is parsed, validated and compared with the remote one, and the screen
+// shows the result of the latest check. Without a config it waits for one to
+// be created.
+func (m model) startConfigWatch() (tea.Model, tea.Cmd) {
+	m.state = stateWatching
+	filename, err := findConfigFile()
+	if err != nil {
+		m.watchHeader = "Waiting for a basic config in this directory, then checking the schema on every save..."
+		return m, func() tea.Msg {
+			filename, err := waitForConfigFile()
+			if err != nil {
+				return errorScreenMsg{errorMessage: err.Error()}
+			}
+			return runWatchCheck(filename, configWatchCheck, true)()
+		}
+	}
+
+	m.watchHeader = fmt.Sprintf("Watching %s, checking the schema on every save...", filename)
+	return m, runWatchCheck(filename, configWatchCheck, true)
+}
+
+// waitForConfigFile blocks until a config is created in the current
+// directory and returns its name
+func waitForConfigFile() (string, error) {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return "", fmt.Errorf("error watching this directory: %v", err)
+	}
+	defer watcher.Close()
+	if err := watcher.Add("."); err != nil {
+		return "", fmt.Errorf("error watching this directory: %v", err)
+	}
+
+	// the config may have been created before the watch started
+	for {
+		if filename, err := findConfigFile(); err == nil {
+			time.Sleep(watchDebounce)
+			return filename, nil
+		}
+		select {
+		case _, ok := <-watcher.Events:
+			if !ok {
+				return "", fmt.Errorf("stopped watching this directory")
+			}
+		case _, ok := <-watcher.Errors:
+			// events were dropped, so look again
+			if !ok {
+				return "", fmt.Errorf("stopped watching this directory")
+			}
+		}
+	}
+}
+
+// configWatchCheck stops at the first step that fails, as a schema that
+// doesn't parse can't be validated and an invalid one has no useful status
+func configWatchCheck(filename string) []string {
+	ok := lipgloss.NewStyle().Foreground(lipgloss.Color("2")).Render("✓")
+	failed := lipgloss.NewStyle().Foreground(lipgloss.Color("9")).Render("✗")
+	lines := []string{fmt.Sprintf("[%s] %s", time.Now().Format("15:04:05"), filename), ""}
+
+	source, firstLine, err := readSchemaSource(filename)
+	if err != nil {
+		return append(lines, fmt.Sprintf("%s Parse: %v", failed, err))
+	}
+	parsed, err := offlineValidateSchema(source, firstLine)
+	if err != nil {
+		return append(lines, fmt.Sprintf("%s Parse: %v", failed, err))
+	}
+	if !parsed.Valid {
+		return append(append(lines, fmt.Sprintf("%s Parse: the schema has errors", failed)), formatValidationResult(parsed)[1:]...)
+	}
+	schemaData, _ := parseSchemaJSON(source)
+	tables, fields := countTablesAndFields(schemaData)
+	lines = append(lines, fmt.Sprintf("%s Parse: %s, %s", ok, countNoun(tables, "table"), countNoun(fields, "field")))
+	if hasFlag("--offline") {
+		return lines
+	}
+
+	result, err := validateFile(filename)
+	if err != nil {
+		return append(lines, fmt.Sprintf("%s Validate: %v", failed, err))
+	}
+	if !result.Valid {
+		return append(append(lines, fmt.Sprintf("%s Validate: %s", failed, validationSummary(result))), formatValidationResult(result)[1:]...)
+	}
+	lines = append(lines, fmt.Sprintf("%s Validate: %s", ok, validationSummary(result)))
+
+	switch msg := checkStatusCmd().(type) {
+	case statusErrorMsg:
+		lines = append(lines, fmt.Sprintf("%s Status: %v", failed, msg.err))
+	case statusMsg:
+		mark := ok
+		switch msg.status {
+		case "", "invalid", "conflict":
+			mark = failed
+		}
+		status := strings.Split(msg.text, "\n")
+		lines = append(lines, fmt.Sprintf("%s Status: %s", mark, status[0]))
+		lines = append(lines, status[1:]...)
+	}
+	return lines
+}